@@ -1,10 +1,16 @@
 package api
 
 import (
+	"time"
+
 	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/levels"
 	"github.com/egor-erm/gota/indicators/momentum"
+	"github.com/egor-erm/gota/indicators/patterns"
 	"github.com/egor-erm/gota/indicators/trend"
 	"github.com/egor-erm/gota/indicators/volatility"
+	"github.com/egor-erm/gota/indicators/volume"
+	"github.com/egor-erm/gota/utils"
 )
 
 // Analyzer - структура для анализа данных
@@ -59,6 +65,47 @@ func (a *Analyzer) ATR(period int) []float64 {
 	return atr.Calculate(a.series)
 }
 
+func (a *Analyzer) NATR(period int) []float64 {
+	natr := volatility.NewNATR(period)
+
+	return natr.Calculate(a.series)
+}
+
+// HistoricalVolatility вычисляет годовую реализованную волатильность выбранной оценкой.
+// Если periodsPerYear <= 0, количество свечей в году определяется по таймфрейму серии
+// для круглосуточной торговли (365 дней в году)
+func (a *Analyzer) HistoricalVolatility(period int, estimator volatility.HVEstimator, periodsPerYear float64) []float64 {
+	if periodsPerYear <= 0 {
+		periodsPerYear = volatility.PeriodsPerYear(gota.Timeframe(a.series), 365, 24*time.Hour)
+	}
+
+	hv := volatility.NewHistoricalVolatility(period, estimator, periodsPerYear)
+
+	return hv.Calculate(a.series)
+}
+
+func (a *Analyzer) ChandelierExit(period int, multiplier float64) ([]float64, []float64) {
+	ce := volatility.NewChandelierExit(period, multiplier)
+
+	result := ce.Calculate(a.series)
+	if result == nil {
+		return nil, nil
+	}
+
+	return result.LongStop, result.ShortStop
+}
+
+func (a *Analyzer) ATRTrailingStop(period int, multiplier float64) ([]float64, []int) {
+	ts := volatility.NewATRTrailingStop(period, multiplier)
+
+	result := ts.Calculate(a.series)
+	if result == nil {
+		return nil, nil
+	}
+
+	return result.Stop, result.Direction
+}
+
 func (a *Analyzer) BollingerBands(period int, stdDev float64) ([]float64, []float64, []float64) {
 	bb := volatility.NewBollingerBands(period, stdDev)
 
@@ -70,6 +117,68 @@ func (a *Analyzer) BollingerBands(period int, stdDev float64) ([]float64, []floa
 	return result.UpperBand, result.MiddleBand, result.LowerBand
 }
 
+// BollingerBandsWithOptions вычисляет полосы Боллинджера с выбранным типом средней линии
+// и видом стандартного отклонения (выборочное или генеральное), включая %B и ширину полос
+func (a *Analyzer) BollingerBandsWithOptions(period int, stdDev float64, maType trend.MAType, sampleStdDev bool) *volatility.BollingerBandsResult {
+	bb := volatility.NewBollingerBands(period, stdDev)
+	bb.SetMAType(maType)
+	bb.SetSampleStdDev(sampleStdDev)
+
+	return bb.Calculate(a.series)
+}
+
+func (a *Analyzer) BollingerPercentB(period int, stdDev float64) []float64 {
+	bb := volatility.NewBollingerBands(period, stdDev)
+
+	result := bb.Calculate(a.series)
+	if result == nil {
+		return nil
+	}
+
+	return result.PercentB
+}
+
+func (a *Analyzer) BollingerBandwidth(period int, stdDev float64) []float64 {
+	bb := volatility.NewBollingerBands(period, stdDev)
+
+	result := bb.Calculate(a.series)
+	if result == nil {
+		return nil
+	}
+
+	return result.Bandwidth
+}
+
+func (a *Analyzer) KeltnerChannels(emaPeriod, atrPeriod int, multiplier float64) ([]float64, []float64, []float64) {
+	kc := volatility.NewKeltnerChannels(emaPeriod, atrPeriod, multiplier)
+
+	result := kc.Calculate(a.series)
+	if result == nil {
+		return nil, nil, nil
+	}
+
+	return result.UpperBand, result.MiddleBand, result.LowerBand
+}
+
+func (a *Analyzer) DonchianChannels(period int) ([]float64, []float64, []float64) {
+	dc := volatility.NewDonchianChannels(period)
+
+	result := dc.Calculate(a.series)
+	if result == nil {
+		return nil, nil, nil
+	}
+
+	return result.UpperBand, result.MiddleBand, result.LowerBand
+}
+
+// Squeeze возвращает для каждой свечи признак нахождения полос Боллинджера внутри каналов Кельтнера
+func (a *Analyzer) Squeeze(bbPeriod int, bbStdDev float64, kcPeriod int, kcMultiplier float64) []bool {
+	bb := volatility.NewBollingerBands(bbPeriod, bbStdDev).Calculate(a.series)
+	kc := volatility.NewKeltnerChannels(kcPeriod, kcPeriod, kcMultiplier).Calculate(a.series)
+
+	return volatility.DetectSqueeze(bb, kc)
+}
+
 func (a *Analyzer) StochRSI(rsiPeriod, stochPeriod, smoothK, smoothD int) ([]float64, []float64) {
 	stochrsi := momentum.NewStochRSI(rsiPeriod, stochPeriod, smoothK, smoothD)
 
@@ -90,3 +199,273 @@ func (a *Analyzer) ADX(period int) ([]float64, []float64, []float64) {
 
 	return result.ADXValues, result.PlusDI, result.MinusDI
 }
+
+func (a *Analyzer) ROC(period int) []float64 {
+	roc := momentum.NewROC(period)
+
+	return roc.Calculate(a.series)
+}
+
+func (a *Analyzer) Momentum(period int) []float64 {
+	mom := momentum.NewMomentum(period)
+
+	return mom.Calculate(a.series)
+}
+
+func (a *Analyzer) CMO(period int) []float64 {
+	cmo := momentum.NewCMO(period)
+
+	return cmo.Calculate(a.series)
+}
+
+func (a *Analyzer) TSI(longPeriod, shortPeriod, signalPeriod int) ([]float64, []float64) {
+	tsi := momentum.NewTSI(longPeriod, shortPeriod, signalPeriod)
+
+	result := tsi.Calculate(a.series)
+	if result == nil {
+		return nil, nil
+	}
+
+	return result.TSI, result.Signal
+}
+
+func (a *Analyzer) MFI(period int) []float64 {
+	mfi := momentum.NewMFI(period)
+
+	return mfi.Calculate(a.series)
+}
+
+func (a *Analyzer) UltimateOscillator(shortPeriod, mediumPeriod, longPeriod int) []float64 {
+	uo := momentum.NewUltimateOscillator(shortPeriod, mediumPeriod, longPeriod)
+
+	return uo.Calculate(a.series)
+}
+
+func (a *Analyzer) AwesomeOscillator(fastPeriod, slowPeriod int) []float64 {
+	ao := momentum.NewAwesomeOscillator(fastPeriod, slowPeriod)
+
+	return ao.Calculate(a.series)
+}
+
+func (a *Analyzer) AcceleratorOscillator(fastPeriod, slowPeriod, signalPeriod int) []float64 {
+	ac := momentum.NewAcceleratorOscillator(fastPeriod, slowPeriod, signalPeriod)
+
+	return ac.Calculate(a.series)
+}
+
+func (a *Analyzer) OBV() []float64 {
+	obv := volume.NewOBV()
+
+	return obv.Calculate(a.series)
+}
+
+func (a *Analyzer) AccumulationDistribution() []float64 {
+	ad := volume.NewAccumulationDistribution()
+
+	return ad.Calculate(a.series)
+}
+
+func (a *Analyzer) ChaikinMoneyFlow(period int) []float64 {
+	cmf := volume.NewChaikinMoneyFlow(period)
+
+	return cmf.Calculate(a.series)
+}
+
+func (a *Analyzer) ChaikinOscillator(fastPeriod, slowPeriod int) []float64 {
+	co := volume.NewChaikinOscillator(fastPeriod, slowPeriod)
+
+	return co.Calculate(a.series)
+}
+
+func (a *Analyzer) ForceIndex(period int) []float64 {
+	fi := volume.NewForceIndex(period)
+
+	return fi.Calculate(a.series)
+}
+
+func (a *Analyzer) EaseOfMovement(period int, divisor float64) []float64 {
+	emv := volume.NewEaseOfMovement(period, divisor)
+
+	return emv.Calculate(a.series)
+}
+
+// VolumeProfile строит профиль объема по последним window свечам (window <= 0 - по всей серии)
+func (a *Analyzer) VolumeProfile(window, bins int, valueArea float64) *volume.VolumeProfileResult {
+	vp := volume.NewVolumeProfile(bins, valueArea)
+
+	return vp.Calculate(a.lastCandles(window))
+}
+
+// lastCandles возвращает последние n свечей серии (n <= 0 - всю серию)
+func (a *Analyzer) lastCandles(n int) gota.Series {
+	if n <= 0 || n >= a.series.Len() {
+		return a.series
+	}
+
+	return a.series.Slice(a.series.Len()-n, a.series.Len())
+}
+
+func (a *Analyzer) UlcerIndex(period int) []float64 {
+	ui := volatility.NewUlcerIndex(period)
+
+	return ui.Calculate(a.series)
+}
+
+// StdDev вычисляет скользящее стандартное отклонение цены закрытия
+func (a *Analyzer) StdDev(period int, sample bool) []float64 {
+	return utils.RollingStdDev(gota.ClosePrices(a.series), period, sample)
+}
+
+// Variance вычисляет скользящую дисперсию цены закрытия
+func (a *Analyzer) Variance(period int, sample bool) []float64 {
+	return utils.RollingVariance(gota.ClosePrices(a.series), period, sample)
+}
+
+// ZScore вычисляет отклонение цены закрытия от скользящего среднего в стандартных отклонениях
+func (a *Analyzer) ZScore(period int) []float64 {
+	return utils.RollingZScore(gota.ClosePrices(a.series), period)
+}
+
+// RollingMin вычисляет минимальную цену закрытия за period свечей
+func (a *Analyzer) RollingMin(period int) []float64 {
+	return utils.RollingMin(gota.ClosePrices(a.series), period)
+}
+
+// RollingMax вычисляет максимальную цену закрытия за period свечей
+func (a *Analyzer) RollingMax(period int) []float64 {
+	return utils.RollingMax(gota.ClosePrices(a.series), period)
+}
+
+// PercentRank вычисляет процентный ранг цены закрытия среди предыдущих цен окна
+func (a *Analyzer) PercentRank(period int) []float64 {
+	return utils.RollingPercentRank(gota.ClosePrices(a.series), period)
+}
+
+// Skewness вычисляет скользящую асимметрию цен закрытия
+func (a *Analyzer) Skewness(period int) []float64 {
+	return utils.RollingSkewness(gota.ClosePrices(a.series), period)
+}
+
+// Kurtosis вычисляет скользящий эксцесс цен закрытия
+func (a *Analyzer) Kurtosis(period int) []float64 {
+	return utils.RollingKurtosis(gota.ClosePrices(a.series), period)
+}
+
+// CandlestickPatterns находит свечные формации с заданными порогами распознавания
+func (a *Analyzer) CandlestickPatterns(config patterns.Config) []patterns.PatternEvent {
+	cp := patterns.NewCandlestickPatterns(config)
+
+	return cp.Calculate(a.series)
+}
+
+// PivotPoints вычисляет для каждой свечи точки разворота по предыдущему периоду.
+// Границы периодов определяются в часовом поясе location (nil - UTC)
+func (a *Analyzer) PivotPoints(pivotType levels.PivotType, period levels.PivotPeriod, location *time.Location) *levels.PivotPointsResult {
+	pp := levels.NewPivotPoints(pivotType, period, location)
+
+	return pp.Calculate(a.series)
+}
+
+// ZigZag возвращает подтвержденные точки разворота с порогом в процентах от цены
+func (a *Analyzer) ZigZag(percent float64) []levels.Swing {
+	zz := levels.NewZigZag(percent)
+
+	return zz.Calculate(a.series)
+}
+
+// ZigZagATR возвращает подтвержденные точки разворота с порогом multiplier * ATR(period)
+func (a *Analyzer) ZigZagATR(period int, multiplier float64) []levels.Swing {
+	zz := levels.NewZigZagATR(period, multiplier)
+
+	return zz.Calculate(a.series)
+}
+
+// Fibonacci строит уровни Фибоначчи по последнему движению ZigZag с порогом в процентах
+func (a *Analyzer) Fibonacci(percent float64) *levels.FibonacciResult {
+	fib := levels.NewFibonacci(levels.NewZigZag(percent))
+
+	return fib.Calculate(a.series)
+}
+
+// FibonacciATR строит уровни Фибоначчи по последнему движению ZigZag с порогом multiplier * ATR(period)
+func (a *Analyzer) FibonacciATR(period int, multiplier float64) *levels.FibonacciResult {
+	fib := levels.NewFibonacci(levels.NewZigZagATR(period, multiplier))
+
+	return fib.Calculate(a.series)
+}
+
+// SupportResistance находит уровни поддержки и сопротивления, группируя точки разворота
+// ZigZag(percent), цены которых отличаются не более чем на tolerance процентов.
+// Уровни с числом касаний меньше minTouches отбрасываются
+func (a *Analyzer) SupportResistance(percent, tolerance float64, minTouches int) *levels.SupportResistanceResult {
+	sr := levels.NewSupportResistance(levels.NewZigZag(percent), tolerance, minTouches)
+
+	return sr.Calculate(a.series)
+}
+
+// Divergences находит дивергенции между ценой (High/Low свечей) и произвольным осциллятором,
+// выровненным по последним свечам
+func (a *Analyzer) Divergences(oscillator []float64, config utils.DivergenceConfig) []utils.Divergence {
+	return utils.FindDivergences(gota.HighPrices(a.series), gota.LowPrices(a.series), oscillator, config)
+}
+
+// RSIDivergences находит дивергенции между ценой и RSI
+func (a *Analyzer) RSIDivergences(period int, config utils.DivergenceConfig) []utils.Divergence {
+	return a.Divergences(a.RSI(period), config)
+}
+
+// MACDDivergences находит дивергенции между ценой и гистограммой MACD
+func (a *Analyzer) MACDDivergences(fast, slow, signal int, config utils.DivergenceConfig) []utils.Divergence {
+	_, _, histogram := a.MACD(fast, slow, signal)
+
+	return a.Divergences(histogram, config)
+}
+
+// StochRSIDivergences находит дивергенции между ценой и линией K StochRSI
+func (a *Analyzer) StochRSIDivergences(rsiPeriod, stochPeriod, smoothK, smoothD int, config utils.DivergenceConfig) []utils.Divergence {
+	k, _ := a.StochRSI(rsiPeriod, stochPeriod, smoothK, smoothD)
+
+	return a.Divergences(k, config)
+}
+
+// OBVDivergences находит дивергенции между ценой и OBV
+func (a *Analyzer) OBVDivergences(config utils.DivergenceConfig) []utils.Divergence {
+	return a.Divergences(a.OBV(), config)
+}
+
+// Crossovers находит пересечения двух линий, выровненных по последним свечам.
+// Индексы событий - индексы свечей серии, время свечи заполнено
+func (a *Analyzer) Crossovers(line1, line2 []float64, options utils.CrossoverOptions) []utils.CrossoverEvent {
+	n := a.series.Len()
+	events := utils.FindCrossoversWithOptions(utils.AlignToLength(line1, n), utils.AlignToLength(line2, n), options)
+
+	return utils.CrossoversWithTimes(events, a.series, n)
+}
+
+// LevelCrosses находит пересечения линии, выровненной по последним свечам, с уровнем level
+// (например, RSI с 30 и 70). Индексы событий - индексы свечей серии, время свечи заполнено
+func (a *Analyzer) LevelCrosses(line []float64, level float64, options utils.CrossoverOptions) []utils.CrossoverEvent {
+	n := a.series.Len()
+	events := utils.FindLevelCrosses(utils.AlignToLength(line, n), level, options)
+
+	return utils.CrossoversWithTimes(events, a.series, n)
+}
+
+// BandEvents находит выходы значений за границы канала и возвраты в него.
+// Все ряды выравниваются по последним свечам, индексы событий - индексы свечей серии
+func (a *Analyzer) BandEvents(values, upper, lower []float64, options utils.CrossoverOptions) []utils.BandEvent {
+	n := a.series.Len()
+	events := utils.FindBandEvents(utils.AlignToLength(values, n), utils.AlignToLength(upper, n), utils.AlignToLength(lower, n), options)
+
+	return utils.BandEventsWithTimes(events, a.series, n)
+}
+
+// BollingerBandEvents находит выходы цены закрытия за полосы Боллинджера и возвраты внутрь полос
+func (a *Analyzer) BollingerBandEvents(period int, stdDev float64, options utils.CrossoverOptions) []utils.BandEvent {
+	upper, _, lower := a.BollingerBands(period, stdDev)
+	if upper == nil {
+		return nil
+	}
+
+	return a.BandEvents(gota.ClosePrices(a.series), upper, lower, options)
+}
@@ -26,6 +26,10 @@ const (
 	ExitBySignal ExitReason = "SIGNAL"
 	ExitByStop   ExitReason = "STOP_LOSS"
 	ExitByTake   ExitReason = "TAKE_PROFIT"
+
+	ExitByTrailingStop ExitReason = "TRAILING_STOP" // Стоп, подтянутый трейлингом или источником стопов
+	ExitByBreakEven    ExitReason = "BREAK_EVEN"    // Стоп, перенесенный в безубыток
+	ExitByTime         ExitReason = "TIME_EXIT"     // Выход по ограничению количества свечей в сделке
 )
 
 // Trade - структура сделки
@@ -37,22 +41,49 @@ type Trade struct {
 	Type          TradeType
 	Profit        float64
 	ProfitPercent float64
+	Commission    float64 // Комиссия за вход и выход
+	SlippageCost  float64 // Потери на проскальзывании при входе и выходе
+	Quantity      float64 // Количество единиц актива
+	Entries       int     // Количество входов, объединенных в сделку (при учете по средней цене)
+	PositionID    int     // Номер позиции: сделки, закрывающие части одной позиции, имеют общий номер
 
 	StopLossPrice   float64
 	TakeProfitPrice float64
 	ExitReason      ExitReason
+
+	EntryIndex       int     // Индекс свечи входа (первого входа при учете по средней цене)
+	ExitIndex        int     // Индекс свечи выхода
+	BarsHeld         int     // Количество свечей от входа до выхода
+	MAE              float64 // Максимальное неблагоприятное отклонение цены от входа
+	MFE              float64 // Максимальное благоприятное отклонение цены от входа
+	MAEPercent       float64 // MAE в процентах от цены входа
+	MFEPercent       float64 // MFE в процентах от цены входа
+	InitialStopPrice float64 // Стоп-лосс при входе (0 - не задан)
+	RMultiple        float64 // Прибыль в единицах начального риска до стопа (0 - без начального стопа)
+
+	exits exitRules
 }
 
 // TradeSignal - сигнал для входа/выхода
 type TradeSignal struct {
 	Time     time.Time
-	Price    float64
+	Price    float64 // Цена лимитной и стоп-лимитной заявки или цена активации стоп-заявки
 	IsEntry  bool
 	Type     TradeType
 	Strength float64
 
+	Kind      OrderKind // Вид заявки (по умолчанию OrderMarket - по цене открытия следующей свечи)
+	StopPrice float64   // Цена активации стоп-лимитной заявки
+	Quantity  float64   // Количество единиц (см. Order.Quantity)
+	Percent   float64   // Доля позиции для частичного выхода
+
 	StopLoss   float64 // например 0.02 = 2%
 	TakeProfit float64 // например 0.05 = 5%
+
+	Trailing        *TrailingStop // Трейлинг-стоп (см. Order.Trailing)
+	BreakEven       float64       // Порог переноса стопа в безубыток (см. Order.BreakEven)
+	BreakEvenOffset float64       // Смещение безубытка от цены входа
+	MaxBars         int           // Ограничение количества свечей в сделке (см. Order.MaxBars)
 }
 
 // Strategy - интерфейс стратегии
@@ -71,39 +102,84 @@ type BacktestResult struct {
 	TotalProfit     float64
 	TotalRealProfit float64
 	TotalReturn     float64
-	MaxDrawdown     float64
+	MaxDrawdown     float64 // Максимальная просадка от пика до минимума в процентах
 	WinRate         float64
 	AvgWin          float64
 	AvgLoss         float64
 	ProfitFactor    float64
-	SharpeRatio     float64
 	Trades          []Trade
-	EquityCurve     []float64
+	Fills           []Fill    // Журнал исполнений
+	EquityCurve     []float64 // Капитал с переоценкой открытых позиций на закрытии каждой свечи
+	CashCurve       []float64 // Денежные средства на закрытии каждой свечи
+	PositionsCurve  []float64 // Рыночная стоимость открытых позиций на закрытии каждой свечи
+	DrawdownCurve   []float64 // Просадка от предыдущего пика капитала в процентах
 	MaxEquity       float64
 	MinEquity       float64
+
+	MaxDrawdownPeakIndex     int           // Индекс свечи пика перед максимальной просадкой (-1 - начальный капитал)
+	MaxDrawdownTroughIndex   int           // Индекс свечи минимума максимальной просадки
+	MaxDrawdownRecoveryIndex int           // Индекс свечи восстановления пика (-1 - не восстановлен)
+	MaxDrawdownDuration      time.Duration // Длительность максимальной просадки от пика до восстановления (или до конца теста)
+	MaxDrawdownRecoveryTime  time.Duration // Время восстановления от минимума до пика (0 - не восстановлен)
+	LongestDrawdownDuration  time.Duration // Самый долгий период ниже предыдущего пика
+
+	Excursions     ExcursionSummary // Распределения MAE/MFE, R-кратных и длительности сделок
+	AmbiguousExits int              // Количество выходов, при которых стоп-лосс и тейк-профит попали в диапазон одной свечи
+
+	SharpeRatio          float64       // Годовой коэффициент Шарпа по доходностям свечей
+	SortinoRatio         float64       // Годовой коэффициент Сортино по доходностям свечей
+	CAGR                 float64       // Среднегодовой темп роста капитала в процентах
+	CalmarRatio          float64       // CAGR к максимальной просадке за последние 36 месяцев теста
+	MARRatio             float64       // CAGR к максимальной просадке за весь тест
+	Exposure             float64       // Доля свечей с открытой позицией в процентах
+	AvgHoldingPeriod     time.Duration // Среднее время удержания сделки
+	Expectancy           float64       // Математическое ожидание прибыли на сделку
+	PayoffRatio          float64       // Отношение средней прибыли к среднему убытку
+	MaxConsecutiveWins   int           // Максимальная серия прибыльных сделок
+	MaxConsecutiveLosses int           // Максимальная серия убыточных сделок
+	TailRatio            float64       // Отношение 95-го перцентиля доходностей свечей к модулю 5-го
+	RecoveryFactor       float64       // Общая прибыль к максимальной просадке в деньгах
 }
 
 // Backtester - структура для бектестинга
 type Backtester struct {
 	initialCapital float64
-	commission     float64
-	positionSize   float64 // в процентах от капитала (0-1)
-	slippage       float64 // проскальзывание в процентах
+	fees           FeeModel
+	sizer          PositionSizer
+	lotSize        float64 // шаг количества (0 - без округления)
+	minNotional    float64 // минимальная сумма входа
+	slippage       SlippageModel
+	stopSource     StopSource
+	pyramiding     int  // максимальное количество входов в позицию одного направления
+	hedgeMode      bool // разрешены одновременные длинная и короткая позиции
+	lotAccounting  LotAccounting
+	riskFreeRate   float64 // годовая безрисковая ставка для коэффициентов Шарпа и Сортино
+	periodsPerYear float64 // количество свечей в году (0 - по таймфрейму свечей)
+	intrabarPolicy IntrabarPolicy
+	lowerTimeframe gota.CandleSeries // свечи младшего таймфрейма для IntrabarLowerTimeframe
 }
 
 // NewBacktester создает новый бектестер
 func NewBacktester(initialCapital float64) *Backtester {
 	return &Backtester{
 		initialCapital: initialCapital,
-		commission:     0.001, // 0.1% по умолчанию
-		positionSize:   1.0,   // 100% капитала
-		slippage:       0.001, // 0.1% по умолчанию
+		fees:           NewPercentFeeModel(0.001),    // 0.1% по умолчанию
+		sizer:          NewPercentOfEquitySizer(1.0), // 100% капитала
+		slippage:       NewFixedSlippage(10),         // 0.1% по умолчанию
+		pyramiding:     1,
+		lotAccounting:  LotFIFO,
+		intrabarPolicy: IntrabarPessimistic,
 	}
 }
 
-// SetCommission устанавливает комиссию
+// SetCommission устанавливает комиссию в долях от объема исполнения (одинаковую для мейкера и тейкера)
 func (b *Backtester) SetCommission(commission float64) {
-	b.commission = commission
+	b.fees = NewPercentFeeModel(commission)
+}
+
+// SetFeeModel устанавливает модель комиссий
+func (b *Backtester) SetFeeModel(fees FeeModel) {
+	b.fees = fees
 }
 
 // SetPositionSize устанавливает размер позиции
@@ -111,175 +187,591 @@ func (b *Backtester) SetPositionSize(size float64) error {
 	if size <= 0 || size > 1 {
 		return errors.New("размер позиции должен быть между 0 и 1")
 	}
-	b.positionSize = size
+	b.sizer = NewPercentOfEquitySizer(size)
 	return nil
 }
 
-// SetSlippage устанавливает проскальзывание
+// SetPositionSizer устанавливает модель размера позиции для входов без явного количества
+func (b *Backtester) SetPositionSizer(sizer PositionSizer) {
+	b.sizer = sizer
+}
+
+// SetLotSize устанавливает шаг количества: размеры входов и частичных выходов округляются вниз до кратного lotSize
+func (b *Backtester) SetLotSize(lotSize float64) {
+	b.lotSize = lotSize
+}
+
+// SetMinNotional устанавливает минимальную сумму входа. Входы на меньшую сумму отклоняются
+func (b *Backtester) SetMinNotional(minNotional float64) {
+	b.minNotional = minNotional
+}
+
+// SetSlippage устанавливает проскальзывание в долях от цены (0.001 = 0.1%)
 func (b *Backtester) SetSlippage(slippage float64) {
-	b.slippage = slippage
+	b.slippage = NewFixedSlippage(slippage * 10000)
 }
 
-// Backtest выполняет бектест стратегии
+// SetSlippageModel устанавливает модель проскальзывания (nil - без проскальзывания).
+// Проскальзывание применяется к рыночным исполнениям и всегда ухудшает цену:
+// покупка дороже, продажа дешевле. Исполнения по лимитной цене не проскальзывают
+func (b *Backtester) SetSlippageModel(model SlippageModel) {
+	b.slippage = model
+}
+
+// SetPyramiding устанавливает максимальное количество входов в позицию одного направления
+func (b *Backtester) SetPyramiding(maxEntries int) error {
+	if maxEntries < 1 {
+		return errors.New("количество входов должно быть не меньше 1")
+	}
+	b.pyramiding = maxEntries
+	return nil
+}
+
+// SetHedgeMode разрешает одновременно держать длинную и короткую позиции.
+// Без режима хеджирования вход против открытой позиции отклоняется
+func (b *Backtester) SetHedgeMode(enabled bool) {
+	b.hedgeMode = enabled
+}
+
+// SetLotAccounting устанавливает способ учета лотов при частичном закрытии позиции
+func (b *Backtester) SetLotAccounting(accounting LotAccounting) {
+	b.lotAccounting = accounting
+}
+
+// SetStopSource устанавливает источник уровней стоп-лосса (например Chandelier Exit).
+// Стоп открытой позиции подтягивается к уровню источника и никогда не отодвигается назад
+func (b *Backtester) SetStopSource(source StopSource) {
+	b.stopSource = source
+}
+
+// SetRiskFreeRate устанавливает годовую безрисковую ставку (0.05 = 5%)
+func (b *Backtester) SetRiskFreeRate(rate float64) {
+	b.riskFreeRate = rate
+}
+
+// SetPeriodsPerYear устанавливает количество свечей в году для приведения коэффициентов к годовым.
+// При 0 количество определяется по таймфрейму свечей для круглосуточной торговли (365 дней в году)
+func (b *Backtester) SetPeriodsPerYear(periods float64) {
+	b.periodsPerYear = periods
+}
+
+// SetIntrabarPolicy устанавливает правило выбора уровня, если стоп-лосс и тейк-профит
+// попали в диапазон одной свечи (по умолчанию IntrabarPessimistic)
+func (b *Backtester) SetIntrabarPolicy(policy IntrabarPolicy) {
+	b.intrabarPolicy = policy
+}
+
+// SetLowerTimeframe устанавливает свечи младшего таймфрейма, отсортированные по времени,
+// и включает правило IntrabarLowerTimeframe
+func (b *Backtester) SetLowerTimeframe(candles gota.CandleSeries) {
+	b.lowerTimeframe = candles
+	b.intrabarPolicy = IntrabarLowerTimeframe
+}
+
+// Backtest выполняет бектест стратегии со списком сигналов
 func (b *Backtester) Backtest(strategy Strategy, candles gota.CandleSeries) *BacktestResult {
+	return b.Run(NewSignalAdapter(strategy), candles)
+}
+
+// Run выполняет бектест событийной стратегии, передавая ей свечи по одной
+func (b *Backtester) Run(strategy EventStrategy, candles gota.CandleSeries) *BacktestResult {
 	if candles.Len() == 0 {
 		return nil
 	}
 
-	// Получаем сигналы от стратегии
-	signals := strategy.Analyze(candles)
-
 	// Инициализируем результат
 	result := &BacktestResult{
-		Trades:      make([]Trade, 0),
-		EquityCurve: make([]float64, candles.Len()),
+		Trades:         make([]Trade, 0),
+		EquityCurve:    make([]float64, candles.Len()),
+		CashCurve:      make([]float64, candles.Len()),
+		PositionsCurve: make([]float64, candles.Len()),
+	}
+
+	// Уровни стопов из источника (если задан)
+	var stopLong, stopShort []float64
+	if b.stopSource != nil {
+		stopLong, stopShort = b.stopSource.StopLevels(candles)
 	}
 
 	// Переменные для отслеживания состояния
-	var currentTrade *Trade
-	equity := b.initialCapital
-	peakEquity := equity
-	maxDrawdown := 0.0
+	levels := newExitLevels(candles)
+	portfolio := newPortfolio(b.initialCapital)
+	ctx := &StrategyContext{candles: candles}
+	fillHandler, _ := strategy.(FillHandler)
+	exposedBars := 0
+
+	notify := func(fill *Fill) {
+		if fill == nil {
+			return
+		}
+
+		result.Fills = append(result.Fills, *fill)
+		if fillHandler != nil {
+			fillHandler.OnFill(ctx, *fill)
+		}
+	}
+
+	// fillPending исполняет активные рыночные (market = true) или лимитные и стоп-заявки
+	fillPending := func(candle gota.Candle, market bool) {
+		for _, order := range append([]*pendingOrder(nil), ctx.pending...) {
+			if order.done || (order.Kind == OrderMarket) != market {
+				continue
+			}
+
+			price, ok := order.fillPrice(candle)
+			if !ok {
+				continue
+			}
+
+			order.done = true
+			maker := isMakerFill(order.Order, price, candle)
+
+			notify(b.executeOrder(ctx, order.Order, price, maker, portfolio, result))
+		}
+
+		ctx.removeDone()
+	}
 
 	// Обрабатываем каждую свечу
 	for i := 0; i < candles.Len(); i++ {
 		candle := candles.At(i)
-		price := candle.GetClosePrice()
-		high := candle.GetHighPrice()
-		low := candle.GetLowPrice()
-
-		// 1. Проверяем SL / TP
-		if currentTrade != nil {
-			exitPrice := 0.0
-			exitReason := ExitReason("")
-
-			if currentTrade.Type == TradeTypeLong {
-				if currentTrade.StopLossPrice > 0 && low <= currentTrade.StopLossPrice {
-					exitPrice = currentTrade.StopLossPrice
-					exitReason = ExitByStop
-				} else if currentTrade.TakeProfitPrice > 0 && high >= currentTrade.TakeProfitPrice {
-					exitPrice = currentTrade.TakeProfitPrice
-					exitReason = ExitByTake
-				}
-			} else {
-				if currentTrade.StopLossPrice > 0 && high >= currentTrade.StopLossPrice {
-					exitPrice = currentTrade.StopLossPrice
-					exitReason = ExitByStop
-				} else if currentTrade.TakeProfitPrice > 0 && low <= currentTrade.TakeProfitPrice {
-					exitPrice = currentTrade.TakeProfitPrice
-					exitReason = ExitByTake
+		ctx.index = i
+		ctx.closed = false
+
+		// 0. Подтягиваем стопы к уровню источника стопов, безубытку и трейлинг-стопу по предыдущей свече
+		if i > 0 {
+			for _, pos := range portfolio.openPositions() {
+				for _, lot := range pos.lots {
+					if i <= len(stopLong) && i <= len(stopShort) {
+						applyStopLevel(lot, stopLong[i-1], stopShort[i-1])
+					}
+					levels.updateExitRules(lot, i-1)
 				}
 			}
+		}
 
-			if exitReason != "" {
-				b.closeTrade(currentTrade, exitPrice, candle.GetStartTime(), exitReason, &equity, result)
-				currentTrade = nil
+		// 1. Снимаем истекшие заявки
+		for _, order := range ctx.pending {
+			if order.expired(i, candle) {
+				order.done = true
 			}
 		}
-
-		// Проверяем сигналы для этой свечи
-		for _, signal := range signals {
-			if !signal.Time.Equal(candle.GetStartTime()) {
-				continue
+		ctx.removeDone()
+
+		// 2. Закрываем по цене открытия лоты, достигшие ограничения по времени,
+		// и исполняем рыночные заявки
+		for _, pos := range portfolio.openPositions() {
+			for _, lot := range append([]*Trade(nil), pos.lots...) {
+				if timeExpired(lot, i) {
+					notify(b.closeLot(ctx, portfolio, lot, candle.GetOpenPrice(), false, ExitByTime, result))
+				}
 			}
-
-			if signal.IsEntry {
-				// Сигнал на вход
-				if currentTrade == nil {
-					// Учитываем проскальзывание
-					entryPrice := price * (1 - b.slippage)
-
-					sl, tp := calcSLTP(
-						entryPrice,
-						signal.StopLoss,
-						signal.TakeProfit,
-						signal.Type,
-					)
-
-					currentTrade = &Trade{
-						EntryTime:       signal.Time,
-						EntryPrice:      entryPrice,
-						Type:            signal.Type,
-						StopLossPrice:   sl,
-						TakeProfitPrice: tp,
-					}
+		}
+		fillPending(candle, true)
+
+		// 3. Проверяем SL / TP каждого лота
+		for _, pos := range portfolio.openPositions() {
+			for _, lot := range append([]*Trade(nil), pos.lots...) {
+				exitPrice, exitReason, ambiguous := b.checkStopTake(lot, candles, i)
+				if ambiguous {
+					result.AmbiguousExits++
 				}
-			} else {
-				// Сигнал на выход
-				if currentTrade != nil && currentTrade.Type == signal.Type {
-					// Учитываем проскальзывание
-					exitPrice := price * (1 - b.slippage)
-
-					b.closeTrade(
-						currentTrade,
-						exitPrice,
-						signal.Time,
-						ExitBySignal,
-						&equity,
-						result,
-					)
-					currentTrade = nil
+				if exitReason != "" {
+					// Тейк-профит исполняется как лимитная заявка, если не было гэпа
+					maker := exitReason == ExitByTake && exitPrice != candle.GetOpenPrice()
+					notify(b.closeLot(ctx, portfolio, lot, exitPrice, maker, exitReason, result))
 				}
 			}
 		}
 
-		// Обновляем кривую капитала
-		result.EquityCurve[i] = equity
+		// 4. Исполняем лимитные и стоп-заявки, цена которых попала в диапазон свечи
+		fillPending(candle, false)
+
+		// 5. Передаем закрытую свечу стратегии, позиции оцениваются по цене закрытия
+		portfolio.price = candle.GetClosePrice()
+		ctx.closed = true
+		strategy.OnCandle(ctx, candle, portfolio)
+
+		// 6. Исполняем заявки по закрытию, остальные делаем активными
+		for len(ctx.orders) > 0 {
+			for _, order := range ctx.takeOrders() {
+				if order.Kind == OrderMarketOnClose {
+					notify(b.executeOrder(ctx, order, candle.GetClosePrice(), false, portfolio, result))
+					continue
+				}
 
-		// Рассчитываем максимальную просадку
-		if equity > peakEquity {
-			peakEquity = equity
+				ctx.pending = append(ctx.pending, &pendingOrder{
+					Order:       order,
+					placedIndex: i,
+					placedTime:  candle.GetStartTime(),
+				})
+			}
 		}
-		drawdown := (peakEquity - equity) / peakEquity
-		if drawdown > maxDrawdown {
-			maxDrawdown = drawdown
+
+		// Обновляем кривую капитала
+		result.EquityCurve[i] = portfolio.Equity()
+		result.CashCurve[i] = portfolio.Cash()
+		result.PositionsCurve[i] = portfolio.PositionsValue()
+
+		if !portfolio.IsFlat() {
+			exposedBars++
 		}
 	}
 
-	// Форсируем выход из открытой позиции в конце
-	if currentTrade != nil {
-		lastCandle := candles.At(candles.Len() - 1)
-
-		b.closeTrade(
-			currentTrade,
-			lastCandle.GetClosePrice(),
-			lastCandle.GetStartTime(),
-			ExitBySignal,
-			&equity,
-			result,
-		)
+	// Форсируем выход из открытых позиций в конце, неисполненные заявки отбрасываются
+	lastCandle := candles.At(candles.Len() - 1)
+	for _, pos := range portfolio.openPositions() {
+		tradeType := pos.lots[0].Type
+		notify(b.closeQuantity(ctx, portfolio, tradeType, pos.quantity(), lastCandle.GetClosePrice(), false, ExitBySignal, result))
 	}
 
+	// Последняя точка кривой капитала учитывает затраты на принудительное закрытие
+	last := candles.Len() - 1
+	result.EquityCurve[last] = portfolio.Equity()
+	result.CashCurve[last] = portfolio.Cash()
+	result.PositionsCurve[last] = portfolio.PositionsValue()
+
 	// Рассчитываем статистику
+	result.Exposure = float64(exposedBars) / float64(candles.Len()) * 100
 	b.calculateStatistics(result, b.initialCapital)
+	b.calculateDrawdowns(result, candles)
+	b.calculatePerformance(result, candles)
+	result.Excursions = summarizeExcursions(result.Trades)
 
 	return result
 }
 
+// checkStopTake проверяет срабатывание стоп-лосса и тейк-профита позиции на свече index.
+// При гэпе за уровень выход происходит по цене открытия. Если в диапазон свечи попали оба уровня,
+// уровень выбирается по правилу IntrabarPolicy, а ambiguous = true.
+// Возвращает цену и причину выхода (пустая причина - выхода нет)
+func (b *Backtester) checkStopTake(trade *Trade, candles gota.CandleSeries, index int) (price float64, reason ExitReason, ambiguous bool) {
+	candle := candles.At(index)
+	open := candle.GetOpenPrice()
+	sl, tp := trade.StopLossPrice, trade.TakeProfitPrice
+
+	// Причина зависит от того, кто последним переместил стоп: трейлинг, безубыток или никто
+	stopReason := trade.exits.stopReason
+	if stopReason == "" {
+		stopReason = ExitByStop
+	}
+
+	// Цена открытия за уровнем (гэп)
+	stopGap := sl > 0 && open <= sl
+	takeGap := tp > 0 && open >= tp
+	if trade.Type == TradeTypeShort {
+		stopGap = sl > 0 && open >= sl
+		takeGap = tp > 0 && open <= tp
+	}
+
+	switch {
+	case stopGap:
+		return open, stopReason, false
+	case takeGap:
+		return open, ExitByTake, false
+	}
+
+	stopHit, takeHit := levelsHit(trade, candle)
+	switch {
+	case stopHit && takeHit:
+		if b.stopHitFirst(trade, candles, index) {
+			return sl, stopReason, true
+		}
+		return tp, ExitByTake, true
+	case stopHit:
+		return sl, stopReason, false
+	case takeHit:
+		return tp, ExitByTake, false
+	}
+
+	return 0, "", false
+}
+
+// isMakerFill определяет, исполнена ли заявка по своей лимитной цене без пересечения спреда.
+// Исполнение по цене открытия при гэпе считается рыночным
+func isMakerFill(order Order, price float64, candle gota.Candle) bool {
+	if order.Kind != OrderLimit && order.Kind != OrderStopLimit {
+		return false
+	}
+
+	return price == order.LimitPrice && price != candle.GetOpenPrice()
+}
+
+// executeOrder исполняет заявку по цене price на текущей свече контекста.
+// Вход отклоняется, если превышено количество входов в позицию или (без режима хеджирования)
+// открыта позиция противоположного направления. Выход отклоняется, если нет позиции
+// его направления. Возвращает исполнение или nil
+func (b *Backtester) executeOrder(ctx *StrategyContext, order Order, price float64, maker bool, portfolio *Portfolio, result *BacktestResult) *Fill {
+	if !order.IsEntry {
+		pos := portfolio.positions[order.Type]
+		if pos == nil {
+			return nil
+		}
+
+		quantity := pos.quantity()
+		if order.Quantity > 0 && order.Quantity < quantity {
+			quantity = b.roundQuantity(order.Quantity)
+		} else if order.Percent > 0 && order.Percent < 1 {
+			quantity = b.roundQuantity(quantity * order.Percent)
+		}
+
+		fill := b.closeQuantity(ctx, portfolio, order.Type, quantity, price, maker, ExitBySignal, result)
+		if fill != nil {
+			fill.OrderID, fill.Kind = order.ID, order.Kind
+		}
+
+		return fill
+	}
+
+	opposite := TradeTypeShort
+	if order.Type == TradeTypeShort {
+		opposite = TradeTypeLong
+	}
+	if !b.hedgeMode && portfolio.positions[opposite] != nil {
+		return nil
+	}
+
+	pos := portfolio.positions[order.Type]
+	if pos != nil && pos.entries >= b.pyramiding {
+		return nil
+	}
+
+	quantity := order.Quantity
+	if quantity <= 0 {
+		stopPrice, _ := calcSLTP(price, order.StopLoss, order.TakeProfit, order.Type)
+
+		// До закрытия свечи ее диапазон еще неизвестен, по закрытию - известен полностью
+		candles := ctx.candles[:ctx.index]
+		if order.Kind == OrderMarketOnClose {
+			candles = ctx.History()
+		}
+
+		quantity = b.sizer.Size(SizingRequest{
+			Type:      order.Type,
+			Equity:    portfolio.Equity(),
+			Price:     price,
+			StopPrice: stopPrice,
+			Candles:   candles,
+			Trades:    result.Trades,
+		})
+	}
+
+	quantity = b.roundQuantity(quantity)
+	if quantity <= 0 || math.IsNaN(quantity) || math.IsInf(quantity, 0) || quantity*price < b.minNotional {
+		return nil
+	}
+
+	entryPrice := b.executionPrice(ctx, price, quantity, order.IsBuy(), maker)
+	fee := b.fees.Fee(quantity*entryPrice, quantity, maker)
+	entryTime := ctx.candles.At(ctx.index).GetStartTime()
+
+	if pos == nil {
+		portfolio.nextPositionID++
+		pos = &position{id: portfolio.nextPositionID}
+		portfolio.positions[order.Type] = pos
+	}
+	pos.entries++
+
+	if b.lotAccounting == LotAverageCost && len(pos.lots) > 0 {
+		// Объединяем вход с открытым лотом по средней цене
+		lot := pos.lots[0]
+		total := lot.Quantity + quantity
+		lot.EntryPrice = (lot.EntryPrice*lot.Quantity + entryPrice*quantity) / total
+		lot.Quantity = total
+		lot.Commission += fee
+		lot.SlippageCost += math.Abs(entryPrice-price) * quantity
+		lot.Entries++
+
+		// Уровни стопа и тейка пересчитываются от новой средней цены, если заданы в заявке
+		sl, tp := calcSLTP(lot.EntryPrice, order.StopLoss, order.TakeProfit, order.Type)
+		if sl > 0 {
+			lot.StopLossPrice = sl
+			lot.InitialStopPrice = sl
+			lot.exits.stopReason = ExitByStop
+		}
+		if tp > 0 {
+			lot.TakeProfitPrice = tp
+		}
+		lot.exits.merge(order)
+	} else {
+		sl, tp := calcSLTP(entryPrice, order.StopLoss, order.TakeProfit, order.Type)
+
+		pos.lots = append(pos.lots, &Trade{
+			EntryTime:       entryTime,
+			EntryPrice:      entryPrice,
+			Type:            order.Type,
+			Commission:      fee,
+			SlippageCost:    math.Abs(entryPrice-price) * quantity,
+			Quantity:        quantity,
+			Entries:         1,
+			PositionID:      pos.id,
+			StopLossPrice:   sl,
+			TakeProfitPrice: tp,
+
+			EntryIndex:       ctx.index,
+			InitialStopPrice: sl,
+
+			exits: newExitRules(order, ctx.index, entryPrice),
+		})
+	}
+
+	return &Fill{
+		OrderID:  order.ID,
+		Kind:     order.Kind,
+		Time:     entryTime,
+		Price:    entryPrice,
+		Type:     order.Type,
+		IsEntry:  true,
+		Quantity: quantity,
+		Maker:    maker,
+		Fee:      fee,
+		Slippage: math.Abs(entryPrice - price),
+	}
+}
+
+// roundQuantity округляет количество вниз до кратного шагу лота
+func (b *Backtester) roundQuantity(quantity float64) float64 {
+	if b.lotSize <= 0 {
+		return quantity
+	}
+
+	// Небольшой допуск защищает от ошибок представления (например, 0.3 / 0.1 = 2.9999...)
+	return math.Floor(quantity/b.lotSize+quantityEpsilon) * b.lotSize
+}
+
+// executionPrice применяет проскальзывание к цене рыночного исполнения против трейдера
+func (b *Backtester) executionPrice(ctx *StrategyContext, price, quantity float64, buy, maker bool) float64 {
+	if maker || b.slippage == nil {
+		return price
+	}
+
+	slippage := math.Abs(b.slippage.Slippage(ctx.candles, ctx.index, price, quantity))
+	if buy {
+		return price + slippage
+	}
+
+	return price - slippage
+}
+
+// closeQuantity закрывает quantity единиц позиции направления tradeType, начиная с самых ранних лотов
+func (b *Backtester) closeQuantity(ctx *StrategyContext, portfolio *Portfolio, tradeType TradeType, quantity, price float64, maker bool, reason ExitReason, result *BacktestResult) *Fill {
+	pos := portfolio.positions[tradeType]
+	if pos == nil || quantity <= 0 {
+		return nil
+	}
+
+	return b.closeLots(ctx, portfolio, pos, append([]*Trade(nil), pos.lots...), quantity, price, maker, reason, result)
+}
+
+// closeLot полностью закрывает лот (например, по его стоп-лоссу)
+func (b *Backtester) closeLot(ctx *StrategyContext, portfolio *Portfolio, lot *Trade, price float64, maker bool, reason ExitReason, result *BacktestResult) *Fill {
+	pos := portfolio.positions[lot.Type]
+
+	return b.closeLots(ctx, portfolio, pos, []*Trade{lot}, lot.Quantity, price, maker, reason, result)
+}
+
+// closeLots закрывает quantity единиц из лотов lots позиции pos по порядку одним исполнением.
+// Каждая закрытая часть лота записывается отдельной сделкой, комиссия входа делится пропорционально количеству
+func (b *Backtester) closeLots(ctx *StrategyContext, portfolio *Portfolio, pos *position, lots []*Trade, quantity, price float64, maker bool, reason ExitReason, result *BacktestResult) *Fill {
+	tradeType := lots[0].Type
+	exitTime := ctx.candles.At(ctx.index).GetStartTime()
+
+	// Выход из длинной позиции - продажа, из короткой - покупка
+	exitPrice := b.executionPrice(ctx, price, quantity, tradeType == TradeTypeShort, maker)
+	fee := b.fees.Fee(quantity*exitPrice, quantity, maker)
+
+	remaining := quantity
+	for _, lot := range lots {
+		if remaining <= quantityEpsilon {
+			break
+		}
+
+		closed := splitLot(lot, math.Min(remaining, lot.Quantity))
+		remaining -= closed.Quantity
+
+		closed.Commission += fee * closed.Quantity / quantity
+		closed.SlippageCost += math.Abs(exitPrice-price) * closed.Quantity
+		recordExcursion(closed, ctx, price)
+		b.closeTrade(closed, exitPrice, exitTime, reason, &portfolio.equity, result)
+	}
+
+	// Убираем закрытые лоты и закрытую позицию
+	open := pos.lots[:0]
+	for _, lot := range pos.lots {
+		if lot.Quantity > quantityEpsilon {
+			open = append(open, lot)
+		}
+	}
+	pos.lots = open
+	if len(pos.lots) == 0 {
+		delete(portfolio.positions, tradeType)
+	}
+
+	return &Fill{
+		Time:     exitTime,
+		Price:    exitPrice,
+		Type:     tradeType,
+		Quantity: quantity - remaining,
+		Reason:   reason,
+		Maker:    maker,
+		Fee:      fee,
+		Slippage: math.Abs(exitPrice - price),
+	}
+}
+
+// splitLot отделяет от лота quantity единиц. Комиссия и проскальзывание входа делятся
+// пропорционально, лот уменьшается на отделенное количество
+func splitLot(lot *Trade, quantity float64) *Trade {
+	closed := *lot
+	if lot.Quantity-quantity <= quantityEpsilon {
+		lot.Quantity = 0
+		return &closed
+	}
+
+	share := quantity / lot.Quantity
+	closed.Quantity = quantity
+	closed.Commission = lot.Commission * share
+	closed.SlippageCost = lot.SlippageCost * share
+
+	lot.Quantity -= quantity
+	lot.Commission -= closed.Commission
+	lot.SlippageCost -= closed.SlippageCost
+
+	return &closed
+}
+
+// closeTrade фиксирует результат сделки с учетом комиссий
 func (b *Backtester) closeTrade(trade *Trade, exitPrice float64, exitTime time.Time, reason ExitReason, equity *float64, result *BacktestResult) {
 	trade.ExitPrice = exitPrice
 	trade.ExitTime = exitTime
 	trade.ExitReason = reason
 
-	var pct float64
-	if trade.Type == TradeTypeLong {
-		pct = (exitPrice - trade.EntryPrice) / trade.EntryPrice
-	} else {
-		pct = (trade.EntryPrice - exitPrice) / trade.EntryPrice
+	profit := (exitPrice - trade.EntryPrice) * trade.Quantity
+	if trade.Type == TradeTypeShort {
+		profit = -profit
 	}
-
-	pct -= b.commission * 2
-
-	posValue := *equity * b.positionSize
-	profit := pct * posValue
+	profit -= trade.Commission
 
 	trade.Profit = profit
-	trade.ProfitPercent = pct * 100
+	trade.ProfitPercent = profit / (trade.EntryPrice * trade.Quantity) * 100
+
+	if risk := math.Abs(trade.EntryPrice-trade.InitialStopPrice) * trade.Quantity; trade.InitialStopPrice > 0 && risk > 0 {
+		trade.RMultiple = profit / risk
+	}
 
 	*equity += profit
 	result.Trades = append(result.Trades, *trade)
 }
 
+// applyStopLevel подтягивает стоп-лосс сделки к уровню источника стопов
+func applyStopLevel(trade *Trade, longLevel, shortLevel float64) {
+	if trade.Type == TradeTypeLong {
+		tightenStop(trade, longLevel, ExitByTrailingStop)
+	} else {
+		tightenStop(trade, shortLevel, ExitByTrailingStop)
+	}
+}
+
 func calcSLTP(entry float64, slPct, tpPct float64, t TradeType) (sl, tp float64) {
 	if t == TradeTypeLong {
 		if slPct > 0 {
@@ -358,8 +850,91 @@ func (b *Backtester) calculateStatistics(result *BacktestResult, initialCapital
 			result.MinEquity = equity
 		}
 	}
+}
+
+// drawdowns строит кривую просадки капитала equity в процентах от предыдущего пика, начиная
+// с капитала start, и находит максимальную просадку. Индекс пика -1 - пиком был капитал start
+func drawdowns(start float64, equity []float64) (curve []float64, maxDrawdown float64, peakIndex, troughIndex int) {
+	curve = make([]float64, len(equity))
+	peakIndex, troughIndex = -1, -1
+
+	peak, currentPeak := start, -1
+	for i, value := range equity {
+		if value >= peak {
+			peak, currentPeak = value, i
+			continue
+		}
+
+		if peak > 0 {
+			curve[i] = (peak - value) / peak * 100
+		}
+
+		if curve[i] > maxDrawdown {
+			maxDrawdown, peakIndex, troughIndex = curve[i], currentPeak, i
+		}
+	}
 
-	result.MaxDrawdown = (result.MaxEquity - result.MinEquity) / result.MaxEquity * 100
+	return curve, maxDrawdown, peakIndex, troughIndex
+}
+
+// calculateDrawdowns строит кривую просадки и находит максимальную просадку от пика до минимума.
+// Пиком до первой свечи считается начальный капитал со временем начала первой свечи
+func (b *Backtester) calculateDrawdowns(result *BacktestResult, candles gota.CandleSeries) {
+	n := len(result.EquityCurve)
+	result.DrawdownCurve, result.MaxDrawdown, result.MaxDrawdownPeakIndex, result.MaxDrawdownTroughIndex =
+		drawdowns(b.initialCapital, result.EquityCurve)
+	result.MaxDrawdownRecoveryIndex = -1
+
+	timeAt := func(i int) time.Time {
+		return candles.At(max(i, 0)).GetStartTime()
+	}
+
+	// Периоды ниже пика: от последней свечи на пике до следующей
+	peakIndex := -1
+	for i, drawdown := range result.DrawdownCurve {
+		if drawdown > 0 {
+			continue
+		}
+		if peakIndex < i-1 {
+			if duration := timeAt(i).Sub(timeAt(peakIndex)); duration > result.LongestDrawdownDuration {
+				result.LongestDrawdownDuration = duration
+			}
+		}
+		peakIndex = i
+	}
+
+	// Период ниже пика, не завершившийся к концу теста
+	if peakIndex < n-1 {
+		if duration := timeAt(n - 1).Sub(timeAt(peakIndex)); duration > result.LongestDrawdownDuration {
+			result.LongestDrawdownDuration = duration
+		}
+	}
+
+	if result.MaxDrawdown == 0 {
+		return
+	}
+
+	// Восстановление - первая свеча после минимума, на которой капитал вернулся к пику
+	peakEquity := b.equityAt(result, result.MaxDrawdownPeakIndex)
+	end := n - 1
+	for i := result.MaxDrawdownTroughIndex + 1; i < n; i++ {
+		if result.EquityCurve[i] >= peakEquity {
+			result.MaxDrawdownRecoveryIndex = i
+			result.MaxDrawdownRecoveryTime = timeAt(i).Sub(timeAt(result.MaxDrawdownTroughIndex))
+			end = i
+			break
+		}
+	}
+	result.MaxDrawdownDuration = timeAt(end).Sub(timeAt(result.MaxDrawdownPeakIndex))
+}
+
+// equityAt возвращает капитал на закрытии свечи index (-1 - начальный капитал)
+func (b *Backtester) equityAt(result *BacktestResult, index int) float64 {
+	if index < 0 {
+		return b.initialCapital
+	}
+
+	return result.EquityCurve[index]
 }
 
 // PrintResults выводит результаты бектеста
@@ -438,9 +1013,29 @@ func (b *Backtester) PrintResults(result *BacktestResult) {
 	fmt.Printf("Общая прибыль: $%.2f\n", result.TotalProfit)
 	fmt.Printf("Общая доходность: %.2f%%\n", result.TotalReturn)
 	fmt.Printf("Макс. просадка: %.2f%%\n", result.MaxDrawdown)
+	fmt.Printf("Длительность макс. просадки: %s\n", result.MaxDrawdownDuration)
 	fmt.Printf("Средняя прибыль: $%.2f\n", result.AvgWin)
 	fmt.Printf("Средний убыток: $%.2f\n", result.AvgLoss)
 	fmt.Printf("Фактор прибыли: %.2f\n", result.ProfitFactor)
+	fmt.Printf("Мат. ожидание сделки: $%.2f\n", result.Expectancy)
+	fmt.Printf("Коэффициент выплат: %.2f\n", result.PayoffRatio)
+	fmt.Printf("Серии: %d прибыльных / %d убыточных подряд\n", result.MaxConsecutiveWins, result.MaxConsecutiveLosses)
+	fmt.Printf("Среднее удержание: %v\n", result.AvgHoldingPeriod)
+	fmt.Printf("Время в позиции: %.1f%%\n", result.Exposure)
+	fmt.Printf("CAGR: %.2f%%\n", result.CAGR)
+	fmt.Printf("Шарп: %.2f, Сортино: %.2f\n", result.SharpeRatio, result.SortinoRatio)
+	fmt.Printf("Калмар: %.2f, MAR: %.2f\n", result.CalmarRatio, result.MARRatio)
+	fmt.Printf("Фактор восстановления: %.2f\n", result.RecoveryFactor)
+	fmt.Printf("Коэффициент хвостов: %.2f\n", result.TailRatio)
+	if result.Excursions.MAEPercent.Count > 0 {
+		fmt.Printf("MAE: среднее %.2f%%, медиана %.2f%%, у прибыльных P90 %.2f%%\n",
+			result.Excursions.MAEPercent.Mean, result.Excursions.MAEPercent.Median, result.Excursions.WinnersMAEPercent.P90)
+		fmt.Printf("MFE: среднее %.2f%%, медиана %.2f%%\n", result.Excursions.MFEPercent.Mean, result.Excursions.MFEPercent.Median)
+	}
+	if result.Excursions.RMultiple.Count > 0 {
+		fmt.Printf("Ожидание в R: %.2f\n", result.Excursions.RMultiple.Mean)
+	}
+	fmt.Printf("Неоднозначных выходов (SL и TP в одной свече): %d\n", result.AmbiguousExits)
 	fmt.Println("==========================")
 }
 
@@ -461,7 +1056,7 @@ func (e *CSVExporter) ExportTrades(trades []Trade, filename string) error {
 	// Заголовки
 	headers := []string{
 		"EntryTime", "ExitTime", "Type", "EntryPrice", "ExitPrice",
-		"Profit", "ProfitPercent",
+		"Quantity", "Profit", "ProfitPercent",
 	}
 	writer.Write(headers)
 
@@ -473,6 +1068,7 @@ func (e *CSVExporter) ExportTrades(trades []Trade, filename string) error {
 			string(trade.Type),
 			strconv.FormatFloat(trade.EntryPrice, 'f', 2, 64),
 			strconv.FormatFloat(trade.ExitPrice, 'f', 2, 64),
+			strconv.FormatFloat(trade.Quantity, 'f', -1, 64),
 			strconv.FormatFloat(trade.Profit, 'f', 2, 64),
 			strconv.FormatFloat(trade.ProfitPercent, 'f', 2, 64),
 		}
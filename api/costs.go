@@ -0,0 +1,111 @@
+package api
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/volatility"
+)
+
+// FeeModel - модель комиссий. Комиссия взимается за каждое исполнение (вход и выход отдельно)
+type FeeModel struct {
+	Maker    float64 // Доля от объема для исполнений по лимитной цене (0.0002 = 0.02%)
+	Taker    float64 // Доля от объема для рыночных исполнений, стопов и гэпов
+	PerTrade float64 // Фиксированная комиссия за исполнение
+	PerUnit  float64 // Комиссия за единицу актива
+	Minimum  float64 // Минимальная комиссия за исполнение
+}
+
+// NewPercentFeeModel создает модель с одинаковой долей от объема для мейкера и тейкера
+func NewPercentFeeModel(rate float64) FeeModel {
+	return FeeModel{Maker: rate, Taker: rate}
+}
+
+// Fee рассчитывает комиссию за исполнение quantity единиц на сумму notional
+func (f FeeModel) Fee(notional, quantity float64, maker bool) float64 {
+	rate := f.Taker
+	if maker {
+		rate = f.Maker
+	}
+
+	fee := math.Abs(notional)*rate + f.PerTrade + math.Abs(quantity)*f.PerUnit
+
+	return math.Max(fee, f.Minimum)
+}
+
+// SlippageModel - модель проскальзывания рыночных исполнений
+type SlippageModel interface {
+	// Slippage возвращает неблагоприятное смещение цены (в единицах цены, не меньше 0)
+	// при исполнении quantity единиц по цене price на свече index
+	Slippage(candles gota.CandleSeries, index int, price, quantity float64) float64
+}
+
+// FixedSlippage - проскальзывание в базисных пунктах от цены
+type FixedSlippage struct {
+	bps float64
+}
+
+// NewFixedSlippage создает проскальзывание bps базисных пунктов (10 = 0.1%)
+func NewFixedSlippage(bps float64) *FixedSlippage {
+	return &FixedSlippage{bps: bps}
+}
+
+func (s *FixedSlippage) Slippage(candles gota.CandleSeries, index int, price, quantity float64) float64 {
+	return price * s.bps / 10000
+}
+
+// VolumeSlippage - проскальзывание, пропорциональное доле исполнения в объеме свечи:
+// price * impact * quantity / volume. Например, при impact = 0.1 исполнение 10% объема
+// свечи сдвигает цену на 1%
+type VolumeSlippage struct {
+	impact float64
+}
+
+func NewVolumeSlippage(impact float64) *VolumeSlippage {
+	return &VolumeSlippage{impact: impact}
+}
+
+func (s *VolumeSlippage) Slippage(candles gota.CandleSeries, index int, price, quantity float64) float64 {
+	volume := candles.At(index).GetVolume()
+	if volume <= 0 {
+		return 0
+	}
+
+	return price * s.impact * math.Abs(quantity) / volume
+}
+
+// ATRSlippage - проскальзывание, равное multiplier * ATR(period) на предыдущей свече.
+// Пока ATR не определен, проскальзывание равно 0
+type ATRSlippage struct {
+	period     int
+	multiplier float64
+
+	// ATR последней серии, чтобы не пересчитывать его на каждом исполнении
+	candles gota.CandleSeries
+	atr     []float64
+}
+
+func NewATRSlippage(period int, multiplier float64) *ATRSlippage {
+	return &ATRSlippage{
+		period:     period,
+		multiplier: multiplier,
+	}
+}
+
+func (s *ATRSlippage) Slippage(candles gota.CandleSeries, index int, price, quantity float64) float64 {
+	if len(candles) == 0 || index < 1 {
+		return 0
+	}
+
+	if len(s.candles) != len(candles) || &s.candles[0] != &candles[0] {
+		s.candles = candles
+		s.atr, _ = volatility.AlignedATR(candles, s.period)
+	}
+
+	if s.atr == nil || math.IsNaN(s.atr[index-1]) {
+		return 0
+	}
+	atr := s.atr[index-1]
+
+	return s.multiplier * atr
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"math"
+	"sort"
+)
+
+// Distribution - сводка распределения значений по сделкам
+type Distribution struct {
+	Count  int
+	Mean   float64
+	StdDev float64
+	Min    float64
+	P25    float64
+	Median float64
+	P75    float64
+	P90    float64
+	Max    float64
+}
+
+// newDistribution рассчитывает сводку распределения значений
+func newDistribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mean := 0.0
+	for _, v := range sorted {
+		mean += v
+	}
+	mean /= float64(len(sorted))
+
+	stdDev := 0.0
+	if len(sorted) > 1 {
+		for _, v := range sorted {
+			stdDev += (v - mean) * (v - mean)
+		}
+		stdDev = math.Sqrt(stdDev / float64(len(sorted)-1))
+	}
+
+	return Distribution{
+		Count:  len(sorted),
+		Mean:   mean,
+		StdDev: stdDev,
+		Min:    sorted[0],
+		P25:    percentile(sorted, 0.25),
+		Median: percentile(sorted, 0.5),
+		P75:    percentile(sorted, 0.75),
+		P90:    percentile(sorted, 0.9),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// ExcursionSummary - распределения MAE/MFE, R-кратных и длительности сделок
+type ExcursionSummary struct {
+	MAEPercent Distribution // Максимальное неблагоприятное отклонение всех сделок
+	MFEPercent Distribution // Максимальное благоприятное отклонение всех сделок
+
+	// WinnersMAEPercent - MAE прибыльных сделок: стоп дальше большинства значений не выбивает будущие прибыльные сделки
+	WinnersMAEPercent Distribution
+	// LosersMFEPercent - MFE убыточных сделок: прибыль, упущенная до разворота
+	LosersMFEPercent Distribution
+
+	RMultiple Distribution // R-кратные сделок с начальным стоп-лоссом (среднее - ожидание в R)
+	BarsHeld  Distribution // Количество свечей в сделке
+}
+
+// recordExcursion записывает в закрываемую сделку индексы свечей входа и выхода и MAE/MFE.
+// Учитываются диапазоны свечей с момента входа, последняя свеча - полностью, если выход
+// происходит по ее закрытию, иначе только цена выхода price
+func recordExcursion(trade *Trade, ctx *StrategyContext, price float64) {
+	trade.ExitIndex = ctx.index
+	trade.BarsHeld = trade.ExitIndex - trade.EntryIndex
+
+	high := math.Max(trade.EntryPrice, price)
+	low := math.Min(trade.EntryPrice, price)
+
+	last := ctx.index - 1
+	if ctx.closed {
+		last = ctx.index
+	}
+
+	for i := trade.exits.trackFrom; i <= last; i++ {
+		candle := ctx.candles.At(i)
+		high = math.Max(high, candle.GetHighPrice())
+		low = math.Min(low, candle.GetLowPrice())
+	}
+
+	if trade.Type == TradeTypeLong {
+		trade.MAE = trade.EntryPrice - low
+		trade.MFE = high - trade.EntryPrice
+	} else {
+		trade.MAE = high - trade.EntryPrice
+		trade.MFE = trade.EntryPrice - low
+	}
+
+	trade.MAEPercent = trade.MAE / trade.EntryPrice * 100
+	trade.MFEPercent = trade.MFE / trade.EntryPrice * 100
+}
+
+// summarizeExcursions рассчитывает распределения по закрытым сделкам
+func summarizeExcursions(trades []Trade) ExcursionSummary {
+	var mae, mfe, winnersMAE, losersMFE, rMultiple, barsHeld []float64
+
+	for _, trade := range trades {
+		mae = append(mae, trade.MAEPercent)
+		mfe = append(mfe, trade.MFEPercent)
+		barsHeld = append(barsHeld, float64(trade.BarsHeld))
+
+		if trade.Profit > 0 {
+			winnersMAE = append(winnersMAE, trade.MAEPercent)
+		} else {
+			losersMFE = append(losersMFE, trade.MFEPercent)
+		}
+
+		if trade.InitialStopPrice > 0 {
+			rMultiple = append(rMultiple, trade.RMultiple)
+		}
+	}
+
+	return ExcursionSummary{
+		MAEPercent:        newDistribution(mae),
+		MFEPercent:        newDistribution(mfe),
+		WinnersMAEPercent: newDistribution(winnersMAE),
+		LosersMFEPercent:  newDistribution(losersMFE),
+		RMultiple:         newDistribution(rMultiple),
+		BarsHeld:          newDistribution(barsHeld),
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/volatility"
+)
+
+// TrailingKind - способ расчета расстояния трейлинг-стопа
+type TrailingKind string
+
+const (
+	// TrailingPercent - стоп на расстоянии Distance (доля, 0.02 = 2%) от лучшей цены с момента входа
+	TrailingPercent TrailingKind = "PERCENT"
+	// TrailingAbsolute - стоп на расстоянии Distance (в единицах цены) от лучшей цены
+	TrailingAbsolute TrailingKind = "ABSOLUTE"
+	// TrailingATR - стоп на расстоянии Distance * ATR(Period) от лучшей цены
+	TrailingATR TrailingKind = "ATR"
+	// TrailingIndicator - стоп по уровням источника Source (например Parabolic SAR)
+	TrailingIndicator TrailingKind = "INDICATOR"
+)
+
+// TrailingStop - трейлинг-стоп позиции. Уровень рассчитывается по закрытой свече и применяется
+// со следующей, стоп только подтягивается и никогда не отодвигается назад
+type TrailingStop struct {
+	Kind     TrailingKind
+	Distance float64    // Расстояние от лучшей цены (см. TrailingKind)
+	Period   int        // Период ATR для TrailingATR
+	Source   StopSource // Источник уровней для TrailingIndicator
+
+	// Activation - прибыль в долях от цены входа по лучшей цене, после которой стоп начинает двигаться (0 - сразу)
+	Activation float64
+}
+
+func NewPercentTrailingStop(percent float64) *TrailingStop {
+	return &TrailingStop{Kind: TrailingPercent, Distance: percent}
+}
+
+func NewAbsoluteTrailingStop(distance float64) *TrailingStop {
+	return &TrailingStop{Kind: TrailingAbsolute, Distance: distance}
+}
+
+func NewATRMultipleTrailingStop(period int, multiplier float64) *TrailingStop {
+	return &TrailingStop{Kind: TrailingATR, Distance: multiplier, Period: period}
+}
+
+func NewIndicatorTrailingStop(source StopSource) *TrailingStop {
+	return &TrailingStop{Kind: TrailingIndicator, Source: source}
+}
+
+// exitRules - правила выхода открытого лота и их состояние
+type exitRules struct {
+	trailing        *TrailingStop
+	breakEven       float64
+	breakEvenOffset float64
+	maxBars         int
+
+	trackFrom  int        // Индекс первой свечи, диапазон которой учитывается в лучшей цене
+	best       float64    // Лучшая цена с момента входа: максимум для длинных, минимум для коротких
+	stopReason ExitReason // Причина выхода при срабатывании текущего уровня стопа
+}
+
+// newExitRules создает правила выхода лота, открытого заявкой order на свече index по цене entryPrice.
+// Диапазон свечи входа учитывается только для рыночных заявок, исполненных по цене открытия:
+// лимитные и стоп-заявки исполняются внутри свечи, а заявки по закрытию - после нее,
+// и часть диапазона свечи приходится на время до входа
+func newExitRules(order Order, index int, entryPrice float64) exitRules {
+	trackFrom := index
+	if order.Kind != OrderMarket {
+		trackFrom = index + 1
+	}
+
+	return exitRules{
+		trailing:        order.Trailing,
+		breakEven:       order.BreakEven,
+		breakEvenOffset: order.BreakEvenOffset,
+		maxBars:         order.MaxBars,
+		trackFrom:       trackFrom,
+		best:            entryPrice,
+		stopReason:      ExitByStop,
+	}
+}
+
+// merge добавляет к правилам лота правила, заданные в заявке на наращивание позиции
+func (r *exitRules) merge(order Order) {
+	if order.Trailing != nil {
+		r.trailing = order.Trailing
+	}
+	if order.BreakEven > 0 {
+		r.breakEven = order.BreakEven
+		r.breakEvenOffset = order.BreakEvenOffset
+	}
+	if order.MaxBars > 0 {
+		r.maxBars = order.MaxBars
+	}
+}
+
+// timeExpired проверяет, достигнуто ли ограничение по количеству свечей в сделке к свече index
+func timeExpired(lot *Trade, index int) bool {
+	return lot.exits.maxBars > 0 && index-lot.EntryIndex >= lot.exits.maxBars
+}
+
+// exitLevels - уровни трейлинг-стопов, рассчитанные один раз за бектест
+type exitLevels struct {
+	candles gota.CandleSeries
+	atr     map[int][]float64
+	sources map[*TrailingStop][2][]float64
+}
+
+func newExitLevels(candles gota.CandleSeries) *exitLevels {
+	return &exitLevels{
+		candles: candles,
+		atr:     make(map[int][]float64),
+		sources: make(map[*TrailingStop][2][]float64),
+	}
+}
+
+// trailingLevel возвращает уровень трейлинг-стопа лота по свече index (NaN - уровня нет)
+func (l *exitLevels) trailingLevel(trailing *TrailingStop, lot *Trade, index int) float64 {
+	long := lot.Type == TradeTypeLong
+
+	distance := 0.0
+	switch trailing.Kind {
+	case TrailingPercent:
+		distance = lot.exits.best * trailing.Distance
+
+	case TrailingAbsolute:
+		distance = trailing.Distance
+
+	case TrailingATR:
+		atr, ok := l.atr[trailing.Period]
+		if !ok {
+			atr, _ = volatility.AlignedATR(l.candles, trailing.Period)
+			l.atr[trailing.Period] = atr
+		}
+		if atr == nil {
+			return math.NaN()
+		}
+		distance = trailing.Distance * atr[index]
+
+	case TrailingIndicator:
+		if trailing.Source == nil {
+			return math.NaN()
+		}
+
+		levels, ok := l.sources[trailing]
+		if !ok {
+			levels[0], levels[1] = trailing.Source.StopLevels(l.candles)
+			l.sources[trailing] = levels
+		}
+
+		side := levels[1]
+		if long {
+			side = levels[0]
+		}
+		if index >= len(side) {
+			return math.NaN()
+		}
+
+		return side[index]
+
+	default:
+		return math.NaN()
+	}
+
+	if long {
+		return lot.exits.best - distance
+	}
+
+	return lot.exits.best + distance
+}
+
+// updateExitRules обновляет лучшую цену лота по закрытой свече index, переносит стоп
+// в безубыток и подтягивает трейлинг-стоп
+func (l *exitLevels) updateExitRules(lot *Trade, index int) {
+	rules := &lot.exits
+	if index < rules.trackFrom {
+		return
+	}
+
+	candle := l.candles.At(index)
+	long := lot.Type == TradeTypeLong
+
+	if long {
+		rules.best = math.Max(rules.best, candle.GetHighPrice())
+	} else {
+		rules.best = math.Min(rules.best, candle.GetLowPrice())
+	}
+
+	// Лучший результат с момента входа в долях от цены входа
+	excursion := (rules.best - lot.EntryPrice) / lot.EntryPrice
+	if !long {
+		excursion = -excursion
+	}
+
+	if rules.breakEven > 0 && excursion >= rules.breakEven {
+		level := lot.EntryPrice * (1 + rules.breakEvenOffset)
+		if !long {
+			level = lot.EntryPrice * (1 - rules.breakEvenOffset)
+		}
+
+		tightenStop(lot, level, ExitByBreakEven)
+	}
+
+	if rules.trailing != nil && excursion >= rules.trailing.Activation {
+		tightenStop(lot, l.trailingLevel(rules.trailing, lot, index), ExitByTrailingStop)
+	}
+}
+
+// tightenStop подтягивает стоп лота к уровню level, если он ближе к цене, чем текущий
+func tightenStop(lot *Trade, level float64, reason ExitReason) {
+	if math.IsNaN(level) || level <= 0 {
+		return
+	}
+
+	if lot.StopLossPrice == 0 ||
+		(lot.Type == TradeTypeLong && level > lot.StopLossPrice) ||
+		(lot.Type == TradeTypeShort && level < lot.StopLossPrice) {
+		lot.StopLossPrice = level
+		lot.exits.stopReason = reason
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"math"
+	"sort"
+
+	"github.com/egor-erm/gota"
+)
+
+// IntrabarPolicy - правило выбора уровня, если стоп-лосс и тейк-профит попали в диапазон одной свечи
+type IntrabarPolicy string
+
+const (
+	// IntrabarPessimistic - первым срабатывает стоп-лосс
+	IntrabarPessimistic IntrabarPolicy = "PESSIMISTIC"
+	// IntrabarOptimistic - первым срабатывает тейк-профит
+	IntrabarOptimistic IntrabarPolicy = "OPTIMISTIC"
+	// IntrabarOpenProximity - первым срабатывает уровень, ближайший к цене открытия (при равенстве - стоп)
+	IntrabarOpenProximity IntrabarPolicy = "OPEN_PROXIMITY"
+	// IntrabarLowerTimeframe - порядок определяется по свечам младшего таймфрейма.
+	// Если свечей нет или оба уровня попали в одну свечу младшего таймфрейма, срабатывает стоп-лосс
+	IntrabarLowerTimeframe IntrabarPolicy = "LOWER_TIMEFRAME"
+)
+
+// stopHitFirst определяет, срабатывает ли стоп-лосс раньше тейк-профита на свече index,
+// если оба уровня попали в ее диапазон
+func (b *Backtester) stopHitFirst(trade *Trade, candles gota.CandleSeries, index int) bool {
+	sl, tp := trade.StopLossPrice, trade.TakeProfitPrice
+
+	switch b.intrabarPolicy {
+	case IntrabarOptimistic:
+		return false
+
+	case IntrabarOpenProximity:
+		open := candles.At(index).GetOpenPrice()
+		return math.Abs(open-sl) <= math.Abs(open-tp)
+
+	case IntrabarLowerTimeframe:
+		for _, candle := range b.lowerTimeframeCandles(candles, index) {
+			stopHit, takeHit := levelsHit(trade, candle)
+			if stopHit {
+				return true
+			}
+			if takeHit {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// lowerTimeframeCandles возвращает свечи младшего таймфрейма, начавшиеся внутри свечи index
+func (b *Backtester) lowerTimeframeCandles(candles gota.CandleSeries, index int) gota.CandleSeries {
+	lower := b.lowerTimeframe
+	if len(lower) == 0 {
+		return nil
+	}
+
+	start := candles.At(index).GetStartTime()
+	end := start.Add(gota.Timeframe(candles))
+	if index+1 < candles.Len() {
+		end = candles.At(index + 1).GetStartTime()
+	}
+
+	first := sort.Search(len(lower), func(i int) bool {
+		return !lower[i].GetStartTime().Before(start)
+	})
+	last := sort.Search(len(lower), func(i int) bool {
+		return !lower[i].GetStartTime().Before(end)
+	})
+
+	return lower[first:last]
+}
+
+// levelsHit проверяет, попали ли стоп-лосс и тейк-профит сделки в диапазон свечи
+func levelsHit(trade *Trade, candle gota.Candle) (stopHit, takeHit bool) {
+	sl, tp := trade.StopLossPrice, trade.TakeProfitPrice
+	high, low := candle.GetHighPrice(), candle.GetLowPrice()
+
+	if trade.Type == TradeTypeLong {
+		return sl > 0 && low <= sl, tp > 0 && high >= tp
+	}
+
+	return sl > 0 && high >= sl, tp > 0 && low <= tp
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/volatility"
+)
+
+// calmarWindow - период, за который считается коэффициент Калмара
+const calmarWindow = 3 * 365 * 24 * time.Hour
+
+// calculatePerformance рассчитывает коэффициенты доходности и риска по кривой капитала и сделкам
+func (b *Backtester) calculatePerformance(result *BacktestResult, candles gota.CandleSeries) {
+	b.calculateTradeStatistics(result)
+
+	n := len(result.EquityCurve)
+	if n == 0 {
+		return
+	}
+
+	// Доходности свечей, первая - относительно начального капитала
+	returns := make([]float64, n)
+	previous := b.initialCapital
+	for i, equity := range result.EquityCurve {
+		if previous != 0 {
+			returns[i] = equity/previous - 1
+		}
+		previous = equity
+	}
+
+	timeframe := gota.Timeframe(candles)
+	periodsPerYear := b.periodsPerYear
+	if periodsPerYear <= 0 {
+		periodsPerYear = volatility.PeriodsPerYear(timeframe, 365, 24*time.Hour)
+	}
+
+	if periodsPerYear > 0 {
+		riskFree := math.Pow(1+b.riskFreeRate, 1/periodsPerYear) - 1
+		result.SharpeRatio, result.SortinoRatio = sharpeSortino(returns, riskFree, periodsPerYear)
+	}
+
+	result.TailRatio = tailRatio(returns)
+
+	// CAGR и MAR за весь тест
+	start := candles.At(0).GetStartTime()
+	end := candles.At(n - 1).GetStartTime().Add(timeframe)
+	final := result.EquityCurve[n-1]
+
+	result.CAGR = cagr(b.initialCapital, final, end.Sub(start))
+	if result.MaxDrawdown > 0 {
+		result.MARRatio = result.CAGR / result.MaxDrawdown
+	}
+
+	// Калмар - за последние 36 месяцев (или за весь тест, если он короче)
+	first := 0
+	for first < n-1 && end.Sub(candles.At(first).GetStartTime()) > calmarWindow {
+		first++
+	}
+
+	startEquity := b.initialCapital
+	if first > 0 {
+		startEquity = result.EquityCurve[first-1]
+	}
+
+	windowCAGR := cagr(startEquity, final, end.Sub(candles.At(first).GetStartTime()))
+	if _, drawdown, _, _ := drawdowns(startEquity, result.EquityCurve[first:]); drawdown > 0 {
+		result.CalmarRatio = windowCAGR / drawdown
+	}
+
+	// Фактор восстановления - прибыль к максимальной просадке в деньгах
+	if result.MaxDrawdown > 0 {
+		drawdown := b.equityAt(result, result.MaxDrawdownPeakIndex) - result.EquityCurve[result.MaxDrawdownTroughIndex]
+		if drawdown > 0 {
+			result.RecoveryFactor = result.TotalProfit / drawdown
+		}
+	}
+}
+
+// calculateTradeStatistics рассчитывает статистику по закрытым сделкам
+func (b *Backtester) calculateTradeStatistics(result *BacktestResult) {
+	if len(result.Trades) == 0 {
+		return
+	}
+
+	var holding time.Duration
+	var wins, losses int
+
+	for _, trade := range result.Trades {
+		holding += trade.ExitTime.Sub(trade.EntryTime)
+
+		if trade.Profit > 0 {
+			wins++
+			losses = 0
+		} else {
+			losses++
+			wins = 0
+		}
+
+		result.MaxConsecutiveWins = max(result.MaxConsecutiveWins, wins)
+		result.MaxConsecutiveLosses = max(result.MaxConsecutiveLosses, losses)
+	}
+
+	result.AvgHoldingPeriod = holding / time.Duration(len(result.Trades))
+	result.Expectancy = result.TotalRealProfit / float64(len(result.Trades))
+
+	if result.AvgLoss != 0 {
+		result.PayoffRatio = math.Abs(result.AvgWin / result.AvgLoss)
+	}
+}
+
+// sharpeSortino рассчитывает годовые коэффициенты Шарпа и Сортино по доходностям свечей.
+// riskFree - безрисковая доходность за одну свечу
+func sharpeSortino(returns []float64, riskFree, periodsPerYear float64) (float64, float64) {
+	if len(returns) < 2 {
+		return 0, 0
+	}
+
+	excess := make([]float64, len(returns))
+	mean := 0.0
+	for i, r := range returns {
+		excess[i] = r - riskFree
+		mean += excess[i]
+	}
+	mean /= float64(len(excess))
+
+	var variance, downside float64
+	for _, r := range excess {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	variance /= float64(len(excess) - 1)
+	downside /= float64(len(excess))
+
+	annualization := math.Sqrt(periodsPerYear)
+
+	var sharpe, sortino float64
+	if variance > 0 {
+		sharpe = mean / math.Sqrt(variance) * annualization
+	}
+	if downside > 0 {
+		sortino = mean / math.Sqrt(downside) * annualization
+	}
+
+	return sharpe, sortino
+}
+
+// tailRatio рассчитывает отношение 95-го перцентиля доходностей к модулю 5-го
+func tailRatio(returns []float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), returns...)
+	sort.Float64s(sorted)
+
+	lower := math.Abs(percentile(sorted, 0.05))
+	if lower == 0 {
+		return 0
+	}
+
+	return percentile(sorted, 0.95) / lower
+}
+
+// percentile возвращает перцентиль q (от 0 до 1) отсортированных значений с линейной интерполяцией
+func percentile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+
+	weight := position - float64(lower)
+
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
+
+// cagr рассчитывает среднегодовой темп роста капитала в процентах
+func cagr(start, end float64, period time.Duration) float64 {
+	years := period.Hours() / (365.25 * 24)
+	if start <= 0 || years <= 0 {
+		return 0
+	}
+	if end <= 0 {
+		return -100
+	}
+
+	return (math.Pow(end/start, 1/years) - 1) * 100
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"time"
+
+	"github.com/egor-erm/gota"
+)
+
+// OrderKind - вид заявки
+type OrderKind string
+
+const (
+	// OrderMarket - рыночная заявка, исполняется по цене открытия следующей свечи
+	OrderMarket OrderKind = "MARKET"
+	// OrderMarketOnClose - рыночная заявка, исполняется сразу по цене закрытия текущей свечи
+	OrderMarketOnClose OrderKind = "MARKET_ON_CLOSE"
+	// OrderLimit - лимитная заявка: покупка не дороже LimitPrice, продажа не дешевле
+	OrderLimit OrderKind = "LIMIT"
+	// OrderStop - стоп-заявка: рыночная заявка, активируемая при касании StopPrice
+	OrderStop OrderKind = "STOP"
+	// OrderStopLimit - стоп-лимитная заявка: лимитная заявка по LimitPrice, активируемая при касании StopPrice
+	OrderStopLimit OrderKind = "STOP_LIMIT"
+)
+
+// TimeInForce - срок действия заявки
+type TimeInForce string
+
+const (
+	// GoodTillCancel - заявка действует до исполнения или отмены
+	GoodTillCancel TimeInForce = "GTC"
+	// GoodTillDate - заявка действует до времени ExpiresAt включительно
+	GoodTillDate TimeInForce = "GTD"
+	// Day - заявка действует до конца календарного дня, в который выставлена
+	Day TimeInForce = "DAY"
+	// ImmediateOrCancel - заявка действует только на следующей свече
+	ImmediateOrCancel TimeInForce = "IOC"
+)
+
+// Order - заявка стратегии
+type Order struct {
+	ID          int         // Идентификатор, присваивается при выставлении
+	Kind        OrderKind   // Вид заявки (по умолчанию OrderMarket)
+	Type        TradeType   // Направление позиции
+	IsEntry     bool        // Вход в позицию или выход из нее
+	LimitPrice  float64     // Цена лимитной и стоп-лимитной заявки
+	StopPrice   float64     // Цена активации стоп- и стоп-лимитной заявки
+	TimeInForce TimeInForce // Срок действия (по умолчанию GoodTillCancel)
+	ExpiresAt   time.Time   // Время окончания действия для GoodTillDate (нулевое - заявка действует как GoodTillCancel)
+
+	// Quantity - количество единиц. Для входа 0 - по размеру позиции бектестера,
+	// для выхода 0 - вся позиция (или доля Percent)
+	Quantity float64
+	// Percent - доля позиции для частичного выхода (0.5 = половина), используется при Quantity = 0
+	Percent float64
+
+	StopLoss   float64 // например 0.02 = 2%
+	TakeProfit float64 // например 0.05 = 5%
+
+	// Trailing - трейлинг-стоп входа (nil - без трейлинга)
+	Trailing *TrailingStop
+	// BreakEven - прибыль в долях от цены входа, после которой стоп переносится в безубыток (0 - без переноса)
+	BreakEven float64
+	// BreakEvenOffset - смещение безубытка от цены входа в долях (например 0.002 для покрытия комиссий)
+	BreakEvenOffset float64
+	// MaxBars - выход по цене открытия через MaxBars свечей после входа (0 - без ограничения)
+	MaxBars int
+}
+
+// IsBuy определяет сторону заявки: вход в длинную позицию и выход из короткой - покупка
+func (o Order) IsBuy() bool {
+	return (o.Type == TradeTypeLong) == o.IsEntry
+}
+
+// Fill - исполнение заявки или выход по стопу/тейку
+type Fill struct {
+	OrderID  int       // Идентификатор заявки (0 - выход по стопу/тейку или принудительное закрытие)
+	Kind     OrderKind // Вид исполненной заявки
+	Time     time.Time
+	Price    float64
+	Type     TradeType
+	IsEntry  bool
+	Quantity float64    // Исполненное количество единиц
+	Reason   ExitReason // Причина выхода (для входов пустая)
+
+	Maker    bool    // Исполнение по лимитной цене (комиссия мейкера, без проскальзывания)
+	Fee      float64 // Комиссия за исполнение
+	Slippage float64 // Проскальзывание в единицах цены
+}
+
+// pendingOrder - активная заявка в ожидании исполнения
+type pendingOrder struct {
+	Order
+	placedIndex int       // Индекс свечи, на которой заявка выставлена
+	placedTime  time.Time // Время свечи, на которой заявка выставлена
+	triggered   bool      // Стоп-лимитная заявка активирована
+	done        bool      // Заявка исполнена, отменена или истекла
+}
+
+// expired проверяет, истек ли срок действия заявки к началу свечи index
+func (o *pendingOrder) expired(index int, candle gota.Candle) bool {
+	switch o.TimeInForce {
+	case GoodTillDate:
+		return !o.ExpiresAt.IsZero() && candle.GetStartTime().After(o.ExpiresAt)
+	case Day:
+		y1, m1, d1 := o.placedTime.Date()
+		y2, m2, d2 := candle.GetStartTime().Date()
+		return y1 != y2 || m1 != m2 || d1 != d2
+	case ImmediateOrCancel:
+		return index > o.placedIndex+1
+	}
+
+	return false
+}
+
+// fillPrice определяет, исполняется ли заявка на свече, и по какой цене.
+// Если цена открытия уже за уровнем заявки (гэп), исполнение происходит по цене открытия.
+// Для стоп-лимитной заявки, активированной внутри свечи, считается, что после касания
+// StopPrice цена успевает дойти до LimitPrice, если он в диапазоне свечи
+func (o *pendingOrder) fillPrice(candle gota.Candle) (float64, bool) {
+	open, high, low := candle.GetOpenPrice(), candle.GetHighPrice(), candle.GetLowPrice()
+	limit, stop := o.LimitPrice, o.StopPrice
+
+	// Продажа рассчитывается как покупка в зеркальных ценах
+	sign := 1.0
+	if !o.IsBuy() {
+		sign = -1
+		open, high, low = -open, -low, -high
+		limit, stop = -limit, -stop
+	}
+
+	price, ok := 0.0, false
+
+	switch o.Kind {
+	case OrderLimit:
+		price, ok = limitFill(open, low, limit)
+
+	case OrderStop:
+		if open >= stop {
+			price, ok = open, true
+		} else if high >= stop {
+			price, ok = stop, true
+		}
+
+	case OrderStopLimit:
+		if o.triggered {
+			price, ok = limitFill(open, low, limit)
+			break
+		}
+
+		if open >= stop {
+			o.triggered = true
+			price, ok = limitFill(open, low, limit)
+		} else if high >= stop {
+			o.triggered = true
+			if stop <= limit {
+				price, ok = stop, true
+			} else if low <= limit {
+				price, ok = limit, true
+			}
+		}
+
+	default:
+		price, ok = open, true
+	}
+
+	return sign * price, ok
+}
+
+// limitFill - исполнение лимитной заявки на покупку
+func limitFill(open, low, limit float64) (float64, bool) {
+	if open <= limit {
+		return open, true
+	}
+	if low <= limit {
+		return limit, true
+	}
+
+	return 0, false
+}
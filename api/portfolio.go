@@ -0,0 +1,166 @@
+package api
+
+// LotAccounting - способ учета лотов при частичном закрытии позиции
+type LotAccounting string
+
+const (
+	// LotFIFO - каждый вход учитывается отдельным лотом, выходы закрывают самые ранние лоты
+	LotFIFO LotAccounting = "FIFO"
+	// LotAverageCost - входы объединяются в один лот со средней ценой входа
+	LotAverageCost LotAccounting = "AVERAGE_COST"
+)
+
+// quantityEpsilon - остаток лота, который считается нулевым
+const quantityEpsilon = 1e-9
+
+// position - позиция одного направления
+type position struct {
+	id      int
+	lots    []*Trade // открытые лоты (при учете по средней цене - один лот)
+	entries int      // количество входов в позицию
+}
+
+// quantity возвращает количество единиц во всех лотах позиции
+func (p *position) quantity() float64 {
+	total := 0.0
+	for _, lot := range p.lots {
+		total += lot.Quantity
+	}
+
+	return total
+}
+
+// aggregate сводит лоты позиции в одну сделку со средней ценой входа
+func (p *position) aggregate() Trade {
+	result := *p.lots[0]
+	result.Quantity = 0
+	result.Commission = 0
+	result.SlippageCost = 0
+	result.Entries = 0
+
+	cost := 0.0
+	for _, lot := range p.lots {
+		result.Quantity += lot.Quantity
+		result.Commission += lot.Commission
+		result.SlippageCost += lot.SlippageCost
+		result.Entries += lot.Entries
+		cost += lot.EntryPrice * lot.Quantity
+	}
+	result.EntryPrice = cost / result.Quantity
+
+	return result
+}
+
+// Portfolio - состояние счета, доступное стратегии
+type Portfolio struct {
+	equity         float64 // капитал с учетом закрытых сделок
+	price          float64 // последняя цена для оценки открытых позиций
+	positions      map[TradeType]*position
+	nextPositionID int
+}
+
+func newPortfolio(equity float64) *Portfolio {
+	return &Portfolio{
+		equity:    equity,
+		positions: make(map[TradeType]*position),
+	}
+}
+
+// Equity возвращает капитал с переоценкой открытых позиций по цене закрытия последней свечи.
+// Комиссии входа открытых лотов уже вычтены
+func (p *Portfolio) Equity() float64 {
+	equity := p.equity
+	for _, pos := range p.positions {
+		for _, lot := range pos.lots {
+			equity += unrealizedProfit(lot, p.price)
+		}
+	}
+
+	return equity
+}
+
+// RealizedEquity возвращает капитал с учетом только закрытых сделок
+func (p *Portfolio) RealizedEquity() float64 {
+	return p.equity
+}
+
+// PositionsValue возвращает рыночную стоимость открытых позиций: длинные со знаком плюс, короткие - минус
+func (p *Portfolio) PositionsValue() float64 {
+	value := 0.0
+	for tradeType, pos := range p.positions {
+		quantity := pos.quantity()
+		if tradeType == TradeTypeShort {
+			quantity = -quantity
+		}
+		value += quantity * p.price
+	}
+
+	return value
+}
+
+// Cash возвращает денежные средства: капитал за вычетом стоимости открытых позиций
+func (p *Portfolio) Cash() float64 {
+	return p.Equity() - p.PositionsValue()
+}
+
+// Position возвращает открытую позицию, сведенную в одну сделку со средней ценой входа.
+// Если открыты позиции обоих направлений (режим хеджирования), возвращается длинная
+func (p *Portfolio) Position() (Trade, bool) {
+	if trade, ok := p.PositionFor(TradeTypeLong); ok {
+		return trade, true
+	}
+
+	return p.PositionFor(TradeTypeShort)
+}
+
+// PositionFor возвращает открытую позицию заданного направления
+func (p *Portfolio) PositionFor(tradeType TradeType) (Trade, bool) {
+	pos := p.positions[tradeType]
+	if pos == nil {
+		return Trade{}, false
+	}
+
+	return pos.aggregate(), true
+}
+
+// Lots возвращает открытые лоты позиции заданного направления
+func (p *Portfolio) Lots(tradeType TradeType) []Trade {
+	pos := p.positions[tradeType]
+	if pos == nil {
+		return nil
+	}
+
+	lots := make([]Trade, len(pos.lots))
+	for i, lot := range pos.lots {
+		lots[i] = *lot
+	}
+
+	return lots
+}
+
+// IsFlat проверяет, что открытых позиций нет
+func (p *Portfolio) IsFlat() bool {
+	return len(p.positions) == 0
+}
+
+// unrealizedProfit возвращает нереализованный результат лота по цене price за вычетом комиссии входа
+func unrealizedProfit(lot *Trade, price float64) float64 {
+	profit := (price - lot.EntryPrice) * lot.Quantity
+	if lot.Type == TradeTypeShort {
+		profit = -profit
+	}
+
+	return profit - lot.Commission
+}
+
+// openPositions возвращает открытые позиции в постоянном порядке: сначала длинная, затем короткая
+func (p *Portfolio) openPositions() []*position {
+	result := make([]*position, 0, 2)
+	for _, tradeType := range []TradeType{TradeTypeLong, TradeTypeShort} {
+		if pos := p.positions[tradeType]; pos != nil {
+			result = append(result, pos)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/volatility"
+)
+
+// SizingRequest - данные для расчета размера позиции при входе
+type SizingRequest struct {
+	Type      TradeType
+	Equity    float64           // Текущий капитал
+	Price     float64           // Ожидаемая цена входа (без проскальзывания)
+	StopPrice float64           // Цена стоп-лосса входа (0 - стоп не задан)
+	Candles   gota.CandleSeries // Свечи, известные на момент входа (текущая - только при входе по закрытию)
+	Trades    []Trade           // Закрытые сделки
+}
+
+// PositionSizer - модель размера позиции
+type PositionSizer interface {
+	// Size возвращает количество единиц для входа (0 и меньше - вход отклоняется)
+	Size(request SizingRequest) float64
+}
+
+// FixedUnitsSizer - постоянное количество единиц
+type FixedUnitsSizer struct {
+	units float64
+}
+
+func NewFixedUnitsSizer(units float64) *FixedUnitsSizer {
+	return &FixedUnitsSizer{units: units}
+}
+
+func (s *FixedUnitsSizer) Size(request SizingRequest) float64 {
+	return s.units
+}
+
+// FixedNotionalSizer - позиция на постоянную сумму
+type FixedNotionalSizer struct {
+	notional float64
+}
+
+func NewFixedNotionalSizer(notional float64) *FixedNotionalSizer {
+	return &FixedNotionalSizer{notional: notional}
+}
+
+func (s *FixedNotionalSizer) Size(request SizingRequest) float64 {
+	return s.notional / request.Price
+}
+
+// PercentOfEquitySizer - позиция на долю текущего капитала
+type PercentOfEquitySizer struct {
+	fraction float64
+}
+
+// NewPercentOfEquitySizer создает модель с долей капитала fraction (1.0 = весь капитал)
+func NewPercentOfEquitySizer(fraction float64) *PercentOfEquitySizer {
+	return &PercentOfEquitySizer{fraction: fraction}
+}
+
+func (s *PercentOfEquitySizer) Size(request SizingRequest) float64 {
+	return request.Equity * s.fraction / request.Price
+}
+
+// FixedFractionalSizer - риск фиксированной доли капитала: при срабатывании стоп-лосса
+// теряется risk * Equity. Без стоп-лосса вход отклоняется
+type FixedFractionalSizer struct {
+	risk float64
+}
+
+// NewFixedFractionalSizer создает модель с риском risk от капитала на сделку (0.01 = 1%)
+func NewFixedFractionalSizer(risk float64) *FixedFractionalSizer {
+	return &FixedFractionalSizer{risk: risk}
+}
+
+func (s *FixedFractionalSizer) Size(request SizingRequest) float64 {
+	distance := math.Abs(request.Price - request.StopPrice)
+	if request.StopPrice <= 0 || distance == 0 {
+		return 0
+	}
+
+	return request.Equity * s.risk / distance
+}
+
+// ATRSizer - нацеливание на волатильность: движение цены на multiplier * ATR(period)
+// меняет капитал на risk * Equity. Пока ATR не определен, вход отклоняется
+type ATRSizer struct {
+	period     int
+	multiplier float64
+	risk       float64
+}
+
+func NewATRSizer(period int, multiplier, risk float64) *ATRSizer {
+	return &ATRSizer{
+		period:     period,
+		multiplier: multiplier,
+		risk:       risk,
+	}
+}
+
+func (s *ATRSizer) Size(request SizingRequest) float64 {
+	atr := volatility.NewATR(s.period).Calculate(request.Candles)
+	if len(atr) == 0 || atr[len(atr)-1] <= 0 {
+		return 0
+	}
+
+	return request.Equity * s.risk / (s.multiplier * atr[len(atr)-1])
+}
+
+// KellySizer - доля капитала по критерию Келли, рассчитанная по закрытым сделкам:
+// f = W - (1 - W) / R, где W - доля прибыльных сделок, R - отношение средней прибыли к среднему убытку в процентах.
+// Доля умножается на multiplier (0.5 - "половина Келли") и ограничивается maxFraction.
+// Пока закрытых сделок меньше minTrades, используется fallback. При доле по Келли не больше 0
+// вход делается уменьшенной долей min(fallback, maxFraction) * multiplier, чтобы доля могла восстановиться
+type KellySizer struct {
+	multiplier  float64
+	maxFraction float64
+	minTrades   int
+	fallback    float64
+}
+
+func NewKellySizer(multiplier, maxFraction float64, minTrades int, fallback float64) *KellySizer {
+	return &KellySizer{
+		multiplier:  multiplier,
+		maxFraction: maxFraction,
+		minTrades:   minTrades,
+		fallback:    fallback,
+	}
+}
+
+func (s *KellySizer) Size(request SizingRequest) float64 {
+	fraction := s.fallback
+	if len(request.Trades) >= s.minTrades {
+		fraction = s.Fraction(request.Trades)
+		if fraction <= 0 {
+			fraction = math.Min(s.fallback, s.maxFraction) * s.multiplier
+		}
+	}
+
+	return request.Equity * fraction / request.Price
+}
+
+// Fraction возвращает долю капитала по сделкам trades
+func (s *KellySizer) Fraction(trades []Trade) float64 {
+	var wins, losses int
+	var totalWin, totalLoss float64
+
+	for _, trade := range trades {
+		if trade.Profit > 0 {
+			wins++
+			totalWin += trade.ProfitPercent
+		} else {
+			losses++
+			totalLoss -= trade.ProfitPercent
+		}
+	}
+
+	if wins == 0 {
+		return 0
+	}
+	if losses == 0 || totalLoss <= 0 {
+		return s.maxFraction
+	}
+
+	winRate := float64(wins) / float64(len(trades))
+	payoff := (totalWin / float64(wins)) / (totalLoss / float64(losses))
+	kelly := winRate - (1-winRate)/payoff
+
+	return math.Max(0, math.Min(kelly*s.multiplier, s.maxFraction))
+}
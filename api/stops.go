@@ -0,0 +1,83 @@
+package api
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/volatility"
+	"github.com/egor-erm/gota/utils"
+)
+
+// StopSource - источник уровней стоп-лосса, рассчитываемых по свечам.
+// Уровень свечи i применяется к открытой позиции начиная со свечи i+1
+type StopSource interface {
+	// StopLevels возвращает уровни стопа для длинных и коротких позиций,
+	// выровненные по свечам (NaN - уровня нет)
+	StopLevels(candles gota.CandleSeries) (long, short []float64)
+}
+
+// ChandelierExitStop - стоп по линиям Chandelier Exit
+type ChandelierExitStop struct {
+	period     int
+	multiplier float64
+}
+
+func NewChandelierExitStop(period int, multiplier float64) *ChandelierExitStop {
+	return &ChandelierExitStop{
+		period:     period,
+		multiplier: multiplier,
+	}
+}
+
+func (s *ChandelierExitStop) StopLevels(candles gota.CandleSeries) (long, short []float64) {
+	result := volatility.NewChandelierExit(s.period, s.multiplier).Calculate(candles)
+	if result == nil {
+		return utils.AlignToLength(nil, candles.Len()), utils.AlignToLength(nil, candles.Len())
+	}
+
+	return utils.AlignToLength(result.LongStop, candles.Len()), utils.AlignToLength(result.ShortStop, candles.Len())
+}
+
+// ATRTrailingStopSource - стоп по линии ATR трейлинг-стопа.
+// Для длинных позиций используется только участок линии под ценой, для коротких - над ценой
+type ATRTrailingStopSource struct {
+	period     int
+	multiplier float64
+}
+
+func NewATRTrailingStopSource(period int, multiplier float64) *ATRTrailingStopSource {
+	return &ATRTrailingStopSource{
+		period:     period,
+		multiplier: multiplier,
+	}
+}
+
+func (s *ATRTrailingStopSource) StopLevels(candles gota.CandleSeries) (long, short []float64) {
+	result := volatility.NewATRTrailingStop(s.period, s.multiplier).Calculate(candles)
+	if result == nil {
+		return utils.AlignToLength(nil, candles.Len()), utils.AlignToLength(nil, candles.Len())
+	}
+
+	longStop, shortStop := splitByDirection(result.Stop, result.Direction)
+
+	return utils.AlignToLength(longStop, candles.Len()), utils.AlignToLength(shortStop, candles.Len())
+}
+
+// splitByDirection разделяет линию стопа на участки для длинных и коротких позиций
+func splitByDirection(stop []float64, direction []int) (long, short []float64) {
+	long = make([]float64, len(stop))
+	short = make([]float64, len(stop))
+
+	for i := range stop {
+		long[i] = math.NaN()
+		short[i] = math.NaN()
+
+		if direction[i] > 0 {
+			long[i] = stop[i]
+		} else {
+			short[i] = stop[i]
+		}
+	}
+
+	return long, short
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// EventStrategy - событийная стратегия. Бектестер вызывает OnCandle на каждой свече
+// по порядку, стратегия видит только уже закрытые свечи и выставляет заявки через контекст
+type EventStrategy interface {
+	// OnCandle вызывается после закрытия свечи
+	OnCandle(ctx *StrategyContext, candle gota.Candle, portfolio *Portfolio)
+	// Name возвращает название стратегии
+	Name() string
+}
+
+// FillHandler - необязательный интерфейс стратегии для получения уведомлений об исполнениях
+type FillHandler interface {
+	// OnFill вызывается после каждого исполнения (вход, выход по сигналу, стоп или тейк)
+	OnFill(ctx *StrategyContext, fill Fill)
+}
+
+// StrategyContext - контекст стратегии на текущей свече
+type StrategyContext struct {
+	candles gota.CandleSeries
+	index   int
+	closed  bool // свеча index закрыта: исполнения происходят по цене закрытия
+	nextID  int
+	orders  []Order         // заявки, выставленные на текущей свече
+	pending []*pendingOrder // активные заявки, ожидающие исполнения
+}
+
+// Index возвращает индекс текущей свечи
+func (c *StrategyContext) Index() int {
+	return c.index
+}
+
+// History возвращает свечи от начала серии до текущей включительно
+func (c *StrategyContext) History() gota.CandleSeries {
+	return c.candles[:c.index+1]
+}
+
+// PlaceOrder выставляет заявку и возвращает ее идентификатор. Заявки обрабатываются после
+// возврата из OnCandle: OrderMarketOnClose исполняется сразу по цене закрытия текущей свечи,
+// остальные становятся активными со следующей свечи. Заявки, выставленные в OnFill,
+// обрабатываются так же, как выставленные в OnCandle текущей свечи
+func (c *StrategyContext) PlaceOrder(order Order) int {
+	c.nextID++
+	order.ID = c.nextID
+
+	if order.Kind == "" {
+		order.Kind = OrderMarket
+	}
+	if order.TimeInForce == "" {
+		order.TimeInForce = GoodTillCancel
+	}
+
+	c.orders = append(c.orders, order)
+
+	return order.ID
+}
+
+// CancelOrder отменяет активную заявку. Возвращает false, если заявка не найдена
+// (уже исполнена, отменена или истекла)
+func (c *StrategyContext) CancelOrder(id int) bool {
+	for i, order := range c.orders {
+		if order.ID == id {
+			c.orders = append(c.orders[:i], c.orders[i+1:]...)
+			return true
+		}
+	}
+
+	for _, order := range c.pending {
+		if order.ID == id && !order.done {
+			order.done = true
+			c.removeDone()
+			return true
+		}
+	}
+
+	return false
+}
+
+// CancelAllOrders отменяет все активные заявки
+func (c *StrategyContext) CancelAllOrders() {
+	c.orders = nil
+	for _, order := range c.pending {
+		order.done = true
+	}
+	c.pending = nil
+}
+
+// OpenOrders возвращает активные заявки
+func (c *StrategyContext) OpenOrders() []Order {
+	orders := make([]Order, 0, len(c.pending)+len(c.orders))
+	for _, order := range c.pending {
+		orders = append(orders, order.Order)
+	}
+
+	return append(orders, c.orders...)
+}
+
+// takeOrders забирает заявки, выставленные на текущей свече
+func (c *StrategyContext) takeOrders() []Order {
+	orders := c.orders
+	c.orders = nil
+
+	return orders
+}
+
+// removeDone удаляет из активных исполненные, отмененные и истекшие заявки
+func (c *StrategyContext) removeDone() {
+	active := make([]*pendingOrder, 0, len(c.pending))
+	for _, order := range c.pending {
+		if !order.done {
+			active = append(active, order)
+		}
+	}
+	c.pending = active
+}
+
+// signalAdapter позволяет запускать стратегию со списком сигналов как событийную.
+// Сигналы рассчитываются один раз по всей серии, поэтому такой стратегии по-прежнему
+// доступны будущие свечи
+type signalAdapter struct {
+	strategy Strategy
+	signals  map[int64][]TradeSignal // Сигналы по времени начала свечи (UnixNano)
+}
+
+// NewSignalAdapter оборачивает стратегию Strategy в EventStrategy
+func NewSignalAdapter(strategy Strategy) EventStrategy {
+	return &signalAdapter{strategy: strategy}
+}
+
+func (s *signalAdapter) Name() string {
+	return s.strategy.Name()
+}
+
+func (s *signalAdapter) OnCandle(ctx *StrategyContext, candle gota.Candle, portfolio *Portfolio) {
+	// Сигналы пересчитываются в начале каждого прогона
+	if ctx.Index() == 0 {
+		s.signals = make(map[int64][]TradeSignal)
+		for _, signal := range s.strategy.Analyze(ctx.candles) {
+			key := signal.Time.UnixNano()
+			s.signals[key] = append(s.signals[key], signal)
+		}
+	}
+
+	for _, signal := range s.signals[candle.GetStartTime().UnixNano()] {
+		order := Order{
+			Kind:       signal.Kind,
+			Type:       signal.Type,
+			IsEntry:    signal.IsEntry,
+			Quantity:   signal.Quantity,
+			Percent:    signal.Percent,
+			StopLoss:   signal.StopLoss,
+			TakeProfit: signal.TakeProfit,
+
+			Trailing:        signal.Trailing,
+			BreakEven:       signal.BreakEven,
+			BreakEvenOffset: signal.BreakEvenOffset,
+			MaxBars:         signal.MaxBars,
+		}
+
+		switch signal.Kind {
+		case OrderLimit:
+			order.LimitPrice = signal.Price
+		case OrderStop:
+			order.StopPrice = signal.Price
+		case OrderStopLimit:
+			order.LimitPrice = signal.Price
+			order.StopPrice = signal.StopPrice
+		}
+
+		ctx.PlaceOrder(order)
+	}
+}
@@ -8,8 +8,13 @@ import (
 	"io"
 	"math"
 	"os"
+	"time"
 
 	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/levels"
+	"github.com/egor-erm/gota/indicators/patterns"
+	"github.com/egor-erm/gota/indicators/volume"
+	"github.com/egor-erm/gota/utils"
 	"github.com/fogleman/gg"
 )
 
@@ -50,19 +55,60 @@ type Visualizer struct {
 	topHeight       int // высота для свечного графика
 	indicatorHeight int // высота для каждого индикатора
 	colors          Colors
+	volumeProfile   *volumeProfileOverlay
+	markers         []Marker
+	levels          []HorizontalLevel
+}
+
+// Marker - отметка у свечи на ценовом графике
+type Marker struct {
+	Index int         // Индекс свечи
+	Price float64     // Цена, от которой рисуется отметка
+	Above bool        // Рисовать над ценой (треугольник вершиной вниз), иначе под ценой
+	Color color.Color // Цвет отметки
+	Label string      // Короткая подпись
+}
+
+// HorizontalLevel - горизонтальный ценовой уровень на ценовом графике
+type HorizontalLevel struct {
+	Price      float64     // Цена уровня
+	StartIndex int         // Индекс свечи, с которой начинается линия (до правого края графика)
+	Color      color.Color // Цвет линии
+	Width      float64     // Толщина линии (0 - 1.0)
+	Label      string      // Подпись у правого края
+}
+
+// volumeProfileOverlay - профиль объема, отображаемый на ценовом графике
+type volumeProfileOverlay struct {
+	profile        *volume.VolumeProfileResult
+	barColor       color.Color
+	valueAreaColor color.Color
+	pocColor       color.Color
 }
 
 // IndicatorConfig - конфигурация индикатора для отображения
 type IndicatorConfig struct {
 	Name      string
 	Type      IndicatorType
-	Data      [][]float64   // данные индикатора (может быть несколько линий)
-	Colors    []color.Color // цвета для каждой линии
-	Labels    []string      // названия линий
-	LineWidth float64       // толщина линии
-	Overlay   bool          // отображать ли индикатор поверх свечей
+	Data      [][]float64     // данные индикатора (может быть несколько линий)
+	Colors    []color.Color   // цвета для каждой линии
+	Labels    []string        // названия линий
+	LineWidth float64         // толщина линии
+	Overlay   bool            // отображать ли индикатор поверх свечей
+	Styles    []LineStyle     // стиль отрисовки каждой линии (по умолчанию - линия)
+	BarColors [][]color.Color // цвета отдельных столбцов для линий-гистограмм (nil - цвет линии)
 }
 
+// LineStyle - способ отрисовки линии индикатора
+type LineStyle string
+
+const (
+	LineStyleLine      LineStyle = "line"
+	LineStyleHistogram LineStyle = "histogram"
+	LineStyleStep      LineStyle = "step"      // ступенчатая линия (только для оверлеев)
+	LineStyleConnected LineStyle = "connected" // ломаная через значения, пропуская NaN (только для оверлеев)
+)
+
 type IndicatorType string
 
 const (
@@ -74,6 +120,29 @@ const (
 	IndicatorStochRSI IndicatorType = "StochRSI"
 	IndicatorATR      IndicatorType = "ATR"
 	IndicatorBB       IndicatorType = "BollingerBands"
+	IndicatorROC      IndicatorType = "ROC"
+	IndicatorMomentum IndicatorType = "Momentum"
+	IndicatorCMO      IndicatorType = "CMO"
+	IndicatorTSI      IndicatorType = "TSI"
+	IndicatorMFI      IndicatorType = "MFI"
+	IndicatorUO       IndicatorType = "UltimateOscillator"
+	IndicatorAO       IndicatorType = "AwesomeOscillator"
+	IndicatorAC       IndicatorType = "AcceleratorOscillator"
+	IndicatorVolume   IndicatorType = "Volume"
+	IndicatorOBV      IndicatorType = "OBV"
+	IndicatorAD       IndicatorType = "AccumulationDistribution"
+	IndicatorCMF      IndicatorType = "ChaikinMoneyFlow"
+	IndicatorCO       IndicatorType = "ChaikinOscillator"
+	IndicatorFI       IndicatorType = "ForceIndex"
+	IndicatorEMV      IndicatorType = "EaseOfMovement"
+	IndicatorKC       IndicatorType = "KeltnerChannels"
+	IndicatorDC       IndicatorType = "DonchianChannels"
+	IndicatorBBPctB   IndicatorType = "BollingerPercentB"
+	IndicatorBBW      IndicatorType = "BollingerBandwidth"
+	IndicatorCE       IndicatorType = "ChandelierExit"
+	IndicatorATRStop  IndicatorType = "ATRTrailingStop"
+	IndicatorPivots   IndicatorType = "PivotPoints"
+	IndicatorZigZag   IndicatorType = "ZigZag"
 )
 
 // Candle - структура для отрисовки свечи
@@ -131,6 +200,14 @@ func (v *Visualizer) AddIndicator(config IndicatorConfig) {
 		alignedData[i] = v.alignIndicatorData(data)
 	}
 	config.Data = alignedData
+
+	if len(config.BarColors) > 0 {
+		alignedColors := make([][]color.Color, len(config.BarColors))
+		for i, colors := range config.BarColors {
+			alignedColors[i] = v.alignBarColors(colors)
+		}
+		config.BarColors = alignedColors
+	}
 	v.indicators = append(v.indicators, config)
 }
 
@@ -198,6 +275,7 @@ func (v *Visualizer) AddMACD(fast, slow, signal int, macdColor, signalColor, his
 			Labels:    []string{"MACD", "Signal", "Histogram"},
 			LineWidth: 1.5,
 			Overlay:   false,
+			Styles:    []LineStyle{LineStyleLine, LineStyleLine, LineStyleHistogram},
 		})
 	}
 }
@@ -222,6 +300,117 @@ func (v *Visualizer) AddBollingerBands(period int, stdDev float64, upperColor, m
 	}
 }
 
+// AddBollingerPercentB добавляет индикатор %B полос Боллинджера
+func (v *Visualizer) AddBollingerPercentB(period int, stdDev float64, c color.Color) {
+	percentB := NewAnalyzer(v.series).BollingerPercentB(period, stdDev)
+	if len(percentB) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("%%B(%d,%.1f)", period, stdDev),
+			Type:      IndicatorBBPctB,
+			Data:      [][]float64{v.alignIndicatorData(percentB)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"%B"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddBollingerBandwidth добавляет индикатор ширины полос Боллинджера
+func (v *Visualizer) AddBollingerBandwidth(period int, stdDev float64, c color.Color) {
+	bandwidth := NewAnalyzer(v.series).BollingerBandwidth(period, stdDev)
+	if len(bandwidth) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("BBW(%d,%.1f)", period, stdDev),
+			Type:      IndicatorBBW,
+			Data:      [][]float64{v.alignIndicatorData(bandwidth)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"BBW"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddKeltnerChannels добавляет каналы Кельтнера
+func (v *Visualizer) AddKeltnerChannels(emaPeriod, atrPeriod int, multiplier float64, upperColor, middleColor, lowerColor color.Color) {
+	upper, middle, lower := NewAnalyzer(v.series).KeltnerChannels(emaPeriod, atrPeriod, multiplier)
+	if upper != nil {
+		v.AddIndicator(IndicatorConfig{
+			Name: fmt.Sprintf("KC(%d,%d,%.1f)", emaPeriod, atrPeriod, multiplier),
+			Type: IndicatorKC,
+			Data: [][]float64{
+				v.alignIndicatorData(upper),
+				v.alignIndicatorData(middle),
+				v.alignIndicatorData(lower),
+			},
+			Colors:    []color.Color{upperColor, middleColor, lowerColor},
+			Labels:    []string{"Upper", "Middle", "Lower"},
+			LineWidth: 1.5,
+			Overlay:   true,
+		})
+	}
+}
+
+// AddDonchianChannels добавляет каналы Дончиана
+func (v *Visualizer) AddDonchianChannels(period int, upperColor, middleColor, lowerColor color.Color) {
+	upper, middle, lower := NewAnalyzer(v.series).DonchianChannels(period)
+	if upper != nil {
+		v.AddIndicator(IndicatorConfig{
+			Name: fmt.Sprintf("DC(%d)", period),
+			Type: IndicatorDC,
+			Data: [][]float64{
+				v.alignIndicatorData(upper),
+				v.alignIndicatorData(middle),
+				v.alignIndicatorData(lower),
+			},
+			Colors:    []color.Color{upperColor, middleColor, lowerColor},
+			Labels:    []string{"Upper", "Middle", "Lower"},
+			LineWidth: 1.5,
+			Overlay:   true,
+		})
+	}
+}
+
+// AddChandelierExit добавляет стоп-линии Chandelier Exit
+func (v *Visualizer) AddChandelierExit(period int, multiplier float64, longColor, shortColor color.Color) {
+	longStop, shortStop := NewAnalyzer(v.series).ChandelierExit(period, multiplier)
+	if longStop != nil {
+		v.AddIndicator(IndicatorConfig{
+			Name: fmt.Sprintf("CE(%d,%.1f)", period, multiplier),
+			Type: IndicatorCE,
+			Data: [][]float64{
+				v.alignIndicatorData(longStop),
+				v.alignIndicatorData(shortStop),
+			},
+			Colors:    []color.Color{longColor, shortColor},
+			Labels:    []string{"Long Stop", "Short Stop"},
+			LineWidth: 1.5,
+			Overlay:   true,
+		})
+	}
+}
+
+// AddATRTrailingStop добавляет ATR трейлинг-стоп. Участки под ценой рисуются цветом longColor, над ценой - shortColor
+func (v *Visualizer) AddATRTrailingStop(period int, multiplier float64, longColor, shortColor color.Color) {
+	stop, direction := NewAnalyzer(v.series).ATRTrailingStop(period, multiplier)
+	if stop != nil {
+		longStop, shortStop := splitByDirection(stop, direction)
+		v.AddIndicator(IndicatorConfig{
+			Name: fmt.Sprintf("ATRStop(%d,%.1f)", period, multiplier),
+			Type: IndicatorATRStop,
+			Data: [][]float64{
+				v.alignIndicatorData(longStop),
+				v.alignIndicatorData(shortStop),
+			},
+			Colors:    []color.Color{longColor, shortColor},
+			Labels:    []string{"Long Stop", "Short Stop"},
+			LineWidth: 1.5,
+			Overlay:   true,
+		})
+	}
+}
+
 // AddStochRSI добавляет StochRSI индикатор
 func (v *Visualizer) AddStochRSI(rsiPeriod, stochPeriod, smoothK, smoothD int, kColor, dColor color.Color) {
 	kLine, dLine := NewAnalyzer(v.series).StochRSI(rsiPeriod, stochPeriod, smoothK, smoothD)
@@ -257,33 +446,519 @@ func (v *Visualizer) AddATR(period int, c color.Color) {
 	}
 }
 
+// AddROC добавляет ROC индикатор
+func (v *Visualizer) AddROC(period int, c color.Color) {
+	roc := NewAnalyzer(v.series).ROC(period)
+	if len(roc) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("ROC(%d)", period),
+			Type:      IndicatorROC,
+			Data:      [][]float64{v.alignIndicatorData(roc)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"ROC"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddMomentum добавляет индикатор Momentum
+func (v *Visualizer) AddMomentum(period int, c color.Color) {
+	mom := NewAnalyzer(v.series).Momentum(period)
+	if len(mom) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("Momentum(%d)", period),
+			Type:      IndicatorMomentum,
+			Data:      [][]float64{v.alignIndicatorData(mom)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"Momentum"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddCMO добавляет CMO индикатор
+func (v *Visualizer) AddCMO(period int, c color.Color) {
+	cmo := NewAnalyzer(v.series).CMO(period)
+	if len(cmo) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("CMO(%d)", period),
+			Type:      IndicatorCMO,
+			Data:      [][]float64{v.alignIndicatorData(cmo)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"CMO"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddTSI добавляет TSI индикатор
+func (v *Visualizer) AddTSI(longPeriod, shortPeriod, signalPeriod int, tsiColor, signalColor color.Color) {
+	tsi, signal := NewAnalyzer(v.series).TSI(longPeriod, shortPeriod, signalPeriod)
+	if len(tsi) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name: fmt.Sprintf("TSI(%d,%d,%d)", longPeriod, shortPeriod, signalPeriod),
+			Type: IndicatorTSI,
+			Data: [][]float64{
+				v.alignIndicatorData(tsi),
+				v.alignIndicatorData(signal),
+			},
+			Colors:    []color.Color{tsiColor, signalColor},
+			Labels:    []string{"TSI", "Signal"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddMFI добавляет MFI индикатор
+func (v *Visualizer) AddMFI(period int, c color.Color) {
+	mfi := NewAnalyzer(v.series).MFI(period)
+	if len(mfi) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("MFI(%d)", period),
+			Type:      IndicatorMFI,
+			Data:      [][]float64{v.alignIndicatorData(mfi)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"MFI"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddUltimateOscillator добавляет Ultimate Oscillator
+func (v *Visualizer) AddUltimateOscillator(shortPeriod, mediumPeriod, longPeriod int, c color.Color) {
+	uo := NewAnalyzer(v.series).UltimateOscillator(shortPeriod, mediumPeriod, longPeriod)
+	if len(uo) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("UO(%d,%d,%d)", shortPeriod, mediumPeriod, longPeriod),
+			Type:      IndicatorUO,
+			Data:      [][]float64{v.alignIndicatorData(uo)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"UO"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddAwesomeOscillator добавляет Awesome Oscillator в виде гистограммы.
+// Столбцы, значение которых выросло относительно предыдущего, рисуются цветом upColor, остальные - downColor
+func (v *Visualizer) AddAwesomeOscillator(fastPeriod, slowPeriod int, upColor, downColor color.Color) {
+	ao := NewAnalyzer(v.series).AwesomeOscillator(fastPeriod, slowPeriod)
+	if len(ao) > 0 {
+		aligned := v.alignIndicatorData(ao)
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("AO(%d,%d)", fastPeriod, slowPeriod),
+			Type:      IndicatorAO,
+			Data:      [][]float64{aligned},
+			Colors:    []color.Color{upColor},
+			Labels:    []string{"AO"},
+			LineWidth: 1.5,
+			Overlay:   false,
+			Styles:    []LineStyle{LineStyleHistogram},
+			BarColors: [][]color.Color{trendBarColors(aligned, upColor, downColor)},
+		})
+	}
+}
+
+// AddAcceleratorOscillator добавляет Accelerator Oscillator в виде гистограммы
+func (v *Visualizer) AddAcceleratorOscillator(fastPeriod, slowPeriod, signalPeriod int, upColor, downColor color.Color) {
+	ac := NewAnalyzer(v.series).AcceleratorOscillator(fastPeriod, slowPeriod, signalPeriod)
+	if len(ac) > 0 {
+		aligned := v.alignIndicatorData(ac)
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("AC(%d,%d,%d)", fastPeriod, slowPeriod, signalPeriod),
+			Type:      IndicatorAC,
+			Data:      [][]float64{aligned},
+			Colors:    []color.Color{upColor},
+			Labels:    []string{"AC"},
+			LineWidth: 1.5,
+			Overlay:   false,
+			Styles:    []LineStyle{LineStyleHistogram},
+			BarColors: [][]color.Color{trendBarColors(aligned, upColor, downColor)},
+		})
+	}
+}
+
+// AddVolume добавляет панель объема в виде гистограммы.
+// Столбцы растущих свечей рисуются цветом upColor, падающих - downColor
+func (v *Visualizer) AddVolume(upColor, downColor color.Color) {
+	if v.series.Len() == 0 {
+		return
+	}
+
+	volumes := make([]float64, v.series.Len())
+	barColors := make([]color.Color, v.series.Len())
+	for i := 0; i < v.series.Len(); i++ {
+		candle := v.series.At(i)
+		volumes[i] = candle.GetVolume()
+
+		if candle.GetClosePrice() >= candle.GetOpenPrice() {
+			barColors[i] = upColor
+		} else {
+			barColors[i] = downColor
+		}
+	}
+
+	v.AddIndicator(IndicatorConfig{
+		Name:      "Volume",
+		Type:      IndicatorVolume,
+		Data:      [][]float64{volumes},
+		Colors:    []color.Color{v.colors.Volume},
+		Labels:    []string{"Volume"},
+		LineWidth: 1.0,
+		Overlay:   false,
+		Styles:    []LineStyle{LineStyleHistogram},
+		BarColors: [][]color.Color{barColors},
+	})
+}
+
+// AddOBV добавляет OBV индикатор
+func (v *Visualizer) AddOBV(c color.Color) {
+	obv := NewAnalyzer(v.series).OBV()
+	if len(obv) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      "OBV",
+			Type:      IndicatorOBV,
+			Data:      [][]float64{v.alignIndicatorData(obv)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"OBV"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddAccumulationDistribution добавляет линию накопления/распределения
+func (v *Visualizer) AddAccumulationDistribution(c color.Color) {
+	ad := NewAnalyzer(v.series).AccumulationDistribution()
+	if len(ad) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      "A/D",
+			Type:      IndicatorAD,
+			Data:      [][]float64{v.alignIndicatorData(ad)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"A/D"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddChaikinMoneyFlow добавляет CMF индикатор
+func (v *Visualizer) AddChaikinMoneyFlow(period int, c color.Color) {
+	cmf := NewAnalyzer(v.series).ChaikinMoneyFlow(period)
+	if len(cmf) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("CMF(%d)", period),
+			Type:      IndicatorCMF,
+			Data:      [][]float64{v.alignIndicatorData(cmf)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"CMF"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddChaikinOscillator добавляет Chaikin индикатор
+func (v *Visualizer) AddChaikinOscillator(fastPeriod, slowPeriod int, c color.Color) {
+	co := NewAnalyzer(v.series).ChaikinOscillator(fastPeriod, slowPeriod)
+	if len(co) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("Chaikin(%d,%d)", fastPeriod, slowPeriod),
+			Type:      IndicatorCO,
+			Data:      [][]float64{v.alignIndicatorData(co)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"Chaikin"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddForceIndex добавляет Force Index индикатор
+func (v *Visualizer) AddForceIndex(period int, c color.Color) {
+	fi := NewAnalyzer(v.series).ForceIndex(period)
+	if len(fi) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("ForceIndex(%d)", period),
+			Type:      IndicatorFI,
+			Data:      [][]float64{v.alignIndicatorData(fi)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"Force Index"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddEaseOfMovement добавляет EMV индикатор
+func (v *Visualizer) AddEaseOfMovement(period int, divisor float64, c color.Color) {
+	emv := NewAnalyzer(v.series).EaseOfMovement(period, divisor)
+	if len(emv) > 0 {
+		v.AddIndicator(IndicatorConfig{
+			Name:      fmt.Sprintf("EMV(%d)", period),
+			Type:      IndicatorEMV,
+			Data:      [][]float64{v.alignIndicatorData(emv)},
+			Colors:    []color.Color{c},
+			Labels:    []string{"EMV"},
+			LineWidth: 1.5,
+			Overlay:   false,
+		})
+	}
+}
+
+// AddVolumeProfile добавляет профиль объема по последним window свечам в виде горизонтальной
+// гистограммы у правого края ценового графика. Корзины зоны стоимости рисуются цветом valueAreaColor
+func (v *Visualizer) AddVolumeProfile(window, bins int, valueArea float64, barColor, valueAreaColor, pocColor color.Color) {
+	profile := NewAnalyzer(v.series).VolumeProfile(window, bins, valueArea)
+	if profile != nil {
+		v.volumeProfile = &volumeProfileOverlay{
+			profile:        profile,
+			barColor:       barColor,
+			valueAreaColor: valueAreaColor,
+			pocColor:       pocColor,
+		}
+	}
+}
+
+// AddPivotPoints добавляет ступенчатые линии точек разворота, рассчитанные по предыдущему периоду
+func (v *Visualizer) AddPivotPoints(pivotType levels.PivotType, period levels.PivotPeriod, location *time.Location, pivotColor, resistanceColor, supportColor color.Color) {
+	result := NewAnalyzer(v.series).PivotPoints(pivotType, period, location)
+	if result == nil {
+		return
+	}
+
+	data := [][]float64{result.PP}
+	colors := []color.Color{pivotColor}
+	labels := []string{"PP"}
+
+	resistances := [][]float64{result.R1, result.R2, result.R3, result.R4}
+	supports := [][]float64{result.S1, result.S2, result.S3, result.S4}
+	for i := range resistances {
+		// Уровни, не определенные выбранным методом, целиком состоят из NaN
+		if !hasValues(resistances[i]) {
+			continue
+		}
+
+		data = append(data, resistances[i], supports[i])
+		colors = append(colors, resistanceColor, supportColor)
+		labels = append(labels, fmt.Sprintf("R%d", i+1), fmt.Sprintf("S%d", i+1))
+	}
+
+	styles := make([]LineStyle, len(data))
+	for i := range styles {
+		styles[i] = LineStyleStep
+	}
+
+	v.AddIndicator(IndicatorConfig{
+		Name:      fmt.Sprintf("Pivots(%s,%s)", pivotType, period),
+		Type:      IndicatorPivots,
+		Data:      data,
+		Colors:    colors,
+		Labels:    labels,
+		LineWidth: 1.0,
+		Overlay:   true,
+		Styles:    styles,
+	})
+}
+
+// hasValues проверяет, что в данных есть хотя бы одно значение, отличное от NaN
+func hasValues(data []float64) bool {
+	for _, val := range data {
+		if !math.IsNaN(val) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddZigZag добавляет ломаную через точки разворота ZigZag с порогом в процентах от цены
+func (v *Visualizer) AddZigZag(percent float64, c color.Color) {
+	swings := NewAnalyzer(v.series).ZigZag(percent)
+	v.addZigZag(fmt.Sprintf("ZigZag(%.1f%%)", percent), swings, c)
+}
+
+// AddZigZagATR добавляет ломаную через точки разворота ZigZag с порогом multiplier * ATR(period)
+func (v *Visualizer) AddZigZagATR(period int, multiplier float64, c color.Color) {
+	swings := NewAnalyzer(v.series).ZigZagATR(period, multiplier)
+	v.addZigZag(fmt.Sprintf("ZigZag(ATR %d,%.1f)", period, multiplier), swings, c)
+}
+
+func (v *Visualizer) addZigZag(name string, swings []levels.Swing, c color.Color) {
+	if len(swings) < 2 {
+		return
+	}
+
+	data := make([]float64, v.series.Len())
+	for i := range data {
+		data[i] = math.NaN()
+	}
+	for _, swing := range swings {
+		data[swing.Index] = swing.Price
+	}
+
+	v.AddIndicator(IndicatorConfig{
+		Name:      name,
+		Type:      IndicatorZigZag,
+		Data:      [][]float64{data},
+		Colors:    []color.Color{c},
+		Labels:    []string{name},
+		LineWidth: 1.5,
+		Overlay:   true,
+		Styles:    []LineStyle{LineStyleConnected},
+	})
+}
+
+// AddFibonacci добавляет уровни Фибоначчи по последнему движению ZigZag с порогом в процентах.
+// Если extensionColor равен nil, уровни расширения не рисуются
+func (v *Visualizer) AddFibonacci(percent float64, retracementColor, extensionColor color.Color) {
+	v.addFibonacci(NewAnalyzer(v.series).Fibonacci(percent), retracementColor, extensionColor)
+}
+
+// AddFibonacciATR добавляет уровни Фибоначчи по последнему движению ZigZag с порогом multiplier * ATR(period)
+func (v *Visualizer) AddFibonacciATR(period int, multiplier float64, retracementColor, extensionColor color.Color) {
+	v.addFibonacci(NewAnalyzer(v.series).FibonacciATR(period, multiplier), retracementColor, extensionColor)
+}
+
+func (v *Visualizer) addFibonacci(fib *levels.FibonacciResult, retracementColor, extensionColor color.Color) {
+	if fib == nil {
+		return
+	}
+
+	for _, level := range fib.Retracements {
+		v.AddHorizontalLevel(HorizontalLevel{
+			Price:      level.Price,
+			StartIndex: fib.Start.Index,
+			Color:      retracementColor,
+			Label:      fmt.Sprintf("%.3f (%.2f)", level.Ratio, level.Price),
+		})
+	}
+
+	if extensionColor == nil {
+		return
+	}
+
+	for _, level := range fib.Extensions {
+		v.AddHorizontalLevel(HorizontalLevel{
+			Price:      level.Price,
+			StartIndex: fib.End.Index,
+			Color:      extensionColor,
+			Label:      fmt.Sprintf("%.3f (%.2f)", level.Ratio, level.Price),
+		})
+	}
+}
+
+// AddSupportResistance добавляет уровни поддержки и сопротивления, начиная линию от первого касания.
+// Толщина линии растет с количеством касаний
+func (v *Visualizer) AddSupportResistance(percent, tolerance float64, minTouches int, supportColor, resistanceColor color.Color) {
+	result := NewAnalyzer(v.series).SupportResistance(percent, tolerance, minTouches)
+	if result == nil {
+		return
+	}
+
+	add := func(level levels.Level, prefix string, c color.Color) {
+		v.AddHorizontalLevel(HorizontalLevel{
+			Price:      level.Price,
+			StartIndex: level.FirstIndex,
+			Color:      c,
+			Width:      1.0 + 0.5*math.Min(float64(level.Touches-1), 3),
+			Label:      fmt.Sprintf("%s x%d (%.2f)", prefix, level.Touches, level.Price),
+		})
+	}
+
+	for _, level := range result.Support {
+		add(level, "S", supportColor)
+	}
+	for _, level := range result.Resistance {
+		add(level, "R", resistanceColor)
+	}
+}
+
+// AddHorizontalLevel добавляет горизонтальный уровень на ценовой график
+func (v *Visualizer) AddHorizontalLevel(level HorizontalLevel) {
+	v.levels = append(v.levels, level)
+}
+
+// AddMarker добавляет отметку на ценовой график
+func (v *Visualizer) AddMarker(marker Marker) {
+	v.markers = append(v.markers, marker)
+}
+
+// AddCandlestickPatterns отмечает найденные свечные формации: бычьи - под свечой, медвежьи и нейтральные - над ней
+func (v *Visualizer) AddCandlestickPatterns(config patterns.Config, bullishColor, bearishColor, neutralColor color.Color) {
+	events := NewAnalyzer(v.series).CandlestickPatterns(config)
+
+	for _, event := range events {
+		candle := v.series.At(event.Index)
+
+		marker := Marker{
+			Index: event.Index,
+			Price: candle.GetHighPrice(),
+			Above: true,
+			Label: event.Type.ShortName(),
+		}
+
+		switch event.Bias {
+		case patterns.Bullish:
+			marker.Price = candle.GetLowPrice()
+			marker.Above = false
+			marker.Color = bullishColor
+		case patterns.Bearish:
+			marker.Color = bearishColor
+		default:
+			marker.Color = neutralColor
+		}
+
+		v.AddMarker(marker)
+	}
+}
+
+// trendBarColors раскрашивает столбцы гистограммы по направлению изменения значения
+func trendBarColors(data []float64, upColor, downColor color.Color) []color.Color {
+	colors := make([]color.Color, len(data))
+	for i := range data {
+		if i == 0 || math.IsNaN(data[i-1]) || data[i] >= data[i-1] {
+			colors[i] = upColor
+		} else {
+			colors[i] = downColor
+		}
+	}
+
+	return colors
+}
+
 // alignIndicatorData обрезает данные индикатора для соответствия свечам
 func (v *Visualizer) alignIndicatorData(indicatorData []float64) []float64 {
 	if len(indicatorData) == 0 {
 		return nil
 	}
 
-	// Вычисляем смещение между данными индикатора и свечами
-	// Индикаторы обычно имеют задержку из-за периода расчета
-	offset := v.series.Len() - len(indicatorData)
+	// Индикаторы обычно короче свечей из-за периода расчета
+	return utils.AlignToLength(indicatorData, v.series.Len())
+}
+
+// alignBarColors выравнивает цвета столбцов с количеством свечей так же, как alignIndicatorData
+func (v *Visualizer) alignBarColors(colors []color.Color) []color.Color {
+	offset := v.series.Len() - len(colors)
 
-	// Если индикатор короче свечей, добавляем NaN в начало
 	if offset > 0 {
-		aligned := make([]float64, v.series.Len())
-		for i := 0; i < offset; i++ {
-			aligned[i] = math.NaN()
-		}
-		copy(aligned[offset:], indicatorData)
+		aligned := make([]color.Color, v.series.Len())
+		copy(aligned[offset:], colors)
 		return aligned
 	}
 
-	// Если индикатор длиннее свечей, обрезаем начало
 	if offset < 0 {
-		return indicatorData[-offset:]
+		return colors[-offset:]
 	}
 
-	// Если длины равны
-	return indicatorData
+	return colors
 }
 
 // Render визуализирует график и возвращает изображение
@@ -301,12 +976,21 @@ func (v *Visualizer) Render() (image.Image, error) {
 	// Рисуем сетку и оси
 	v.drawGrid(dc)
 
+	// Рисуем профиль объема под свечами
+	v.drawVolumeProfile(dc)
+
 	// Рисуем свечной график
 	v.drawCandles(dc)
 
 	// Рисуем оверлейные индикаторы (на том же графике, что и свечи)
 	v.drawOverlayIndicators(dc)
 
+	// Рисуем горизонтальные уровни
+	v.drawHorizontalLevels(dc)
+
+	// Рисуем отметки (свечные формации и т.д.)
+	v.drawMarkers(dc)
+
 	// Рассчитываем высоту для каждого индикатора
 	indicatorCount := 0
 	for _, ind := range v.indicators {
@@ -484,6 +1168,49 @@ func (v *Visualizer) drawCandles(dc *gg.Context) {
 	}
 }
 
+// drawVolumeProfile рисует профиль объема горизонтальными столбцами от правого края ценового графика
+func (v *Visualizer) drawVolumeProfile(dc *gg.Context) {
+	if v.volumeProfile == nil || len(v.candles) == 0 {
+		return
+	}
+
+	profile := v.volumeProfile.profile
+	maxVolume := profile.Levels[profile.POCIndex].Volume
+	if maxVolume == 0 {
+		return
+	}
+
+	minPrice, maxPrice := v.getPriceRange()
+	priceRange := maxPrice - minPrice
+	graphHeight := float64(v.topHeight - v.margin*2)
+
+	// Самый длинный столбец занимает четверть ширины графика
+	rightX := float64(v.width - v.margin)
+	maxWidth := float64(v.width-2*v.margin) * 0.25
+
+	for i, level := range profile.Levels {
+		topY := float64(v.margin) + v.priceToY(level.PriceHigh, minPrice, priceRange, graphHeight)
+		bottomY := float64(v.margin) + v.priceToY(level.PriceLow, minPrice, priceRange, graphHeight)
+		width := maxWidth * level.Volume / maxVolume
+
+		if i >= profile.ValueAreaLowIndex && i <= profile.ValueAreaHighIndex {
+			dc.SetColor(v.volumeProfile.valueAreaColor)
+		} else {
+			dc.SetColor(v.volumeProfile.barColor)
+		}
+
+		dc.DrawRectangle(rightX-width, topY+0.5, width, math.Max(bottomY-topY-1, 1))
+		dc.Fill()
+	}
+
+	// Линия POC
+	pocY := float64(v.margin) + v.priceToY(profile.POC, minPrice, priceRange, graphHeight)
+	dc.SetColor(v.volumeProfile.pocColor)
+	dc.SetLineWidth(1.5)
+	dc.DrawLine(float64(v.margin), pocY, rightX, pocY)
+	dc.Stroke()
+}
+
 // drawOverlayIndicators рисует индикаторы поверх свечей
 func (v *Visualizer) drawOverlayIndicators(dc *gg.Context) {
 	minPrice, maxPrice := v.getPriceRange()
@@ -508,11 +1235,29 @@ func (v *Visualizer) drawOverlayIndicators(dc *gg.Context) {
 				dc.SetColor(lineColor)
 				dc.SetLineWidth(ind.LineWidth)
 
+				if lineStyle(ind, lineIdx) == LineStyleStep {
+					// Рисуем ступенчатую линию: горизонтальный отрезок на всю ширину каждой свечи
+					for i, val := range lineData {
+						if math.IsNaN(val) {
+							continue
+						}
+
+						y := float64(v.margin) + v.priceToY(val, minPrice, priceRange, graphHeight)
+						dc.DrawLine(v.candles[i].X-v.candleSpacing/2, y, v.candles[i].X+v.candleSpacing/2, y)
+						dc.Stroke()
+					}
+					continue
+				}
+
 				// Рисуем линию, используя те же X координаты, что и у свечей
+				connected := lineStyle(ind, lineIdx) == LineStyleConnected
 				startX, startY := -1.0, -1.0
 				for i, val := range lineData {
 					if math.IsNaN(val) {
-						startX, startY = -1.0, -1.0
+						// Соединенная линия не прерывается на пропусках
+						if !connected {
+							startX, startY = -1.0, -1.0
+						}
 						continue
 					}
 
@@ -531,6 +1276,97 @@ func (v *Visualizer) drawOverlayIndicators(dc *gg.Context) {
 	}
 }
 
+// drawHorizontalLevels рисует горизонтальные уровни от начальной свечи до правого края графика
+func (v *Visualizer) drawHorizontalLevels(dc *gg.Context) {
+	if len(v.levels) == 0 || len(v.candles) == 0 {
+		return
+	}
+
+	minPrice, maxPrice := v.getPriceRange()
+	priceRange := maxPrice - minPrice
+	graphHeight := float64(v.topHeight - v.margin*2)
+	rightX := float64(v.width - v.margin)
+	fontLoaded := dc.LoadFontFace("", 9) == nil
+
+	for _, level := range v.levels {
+		startX := float64(v.margin)
+		if level.StartIndex > 0 && level.StartIndex < len(v.candles) {
+			startX = v.candles[level.StartIndex].X
+		}
+
+		width := level.Width
+		if width == 0 {
+			width = 1.0
+		}
+
+		y := float64(v.margin) + v.priceToY(level.Price, minPrice, priceRange, graphHeight)
+		dc.SetColor(level.Color)
+		dc.SetLineWidth(width)
+		dc.DrawLine(startX, y, rightX, y)
+		dc.Stroke()
+
+		if fontLoaded && level.Label != "" {
+			dc.DrawStringAnchored(level.Label, rightX-4, y-6, 1, 0.5)
+		}
+	}
+}
+
+// drawMarkers рисует отметки треугольниками над или под свечами.
+// Несколько отметок у одной свечи складываются стопкой
+func (v *Visualizer) drawMarkers(dc *gg.Context) {
+	if len(v.markers) == 0 || len(v.candles) == 0 {
+		return
+	}
+
+	minPrice, maxPrice := v.getPriceRange()
+	priceRange := maxPrice - minPrice
+	graphHeight := float64(v.topHeight - v.margin*2)
+
+	size := math.Max(math.Min(v.candleWidth, 10), 4)
+	fontLoaded := dc.LoadFontFace("", 9) == nil
+	stacked := make(map[int]int) // количество уже нарисованных отметок у свечи (со знаком стороны)
+
+	for _, marker := range v.markers {
+		if marker.Index < 0 || marker.Index >= len(v.candles) {
+			continue
+		}
+
+		key := marker.Index + 1
+		if !marker.Above {
+			key = -key
+		}
+		level := stacked[key]
+		stacked[key]++
+
+		x := v.candles[marker.Index].X
+		y := float64(v.margin) + v.priceToY(marker.Price, minPrice, priceRange, graphHeight)
+		step := (size + 4) * float64(level)
+
+		dc.SetColor(marker.Color)
+		if marker.Above {
+			tip := y - 4 - step
+			dc.MoveTo(x, tip)
+			dc.LineTo(x-size/2, tip-size)
+			dc.LineTo(x+size/2, tip-size)
+		} else {
+			tip := y + 4 + step
+			dc.MoveTo(x, tip)
+			dc.LineTo(x-size/2, tip+size)
+			dc.LineTo(x+size/2, tip+size)
+		}
+		dc.ClosePath()
+		dc.Fill()
+
+		if fontLoaded && marker.Label != "" {
+			if marker.Above {
+				dc.DrawStringAnchored(marker.Label, x, y-4-step-size-6, 0.5, 0.5)
+			} else {
+				dc.DrawStringAnchored(marker.Label, x, y+4+step+size+6, 0.5, 0.5)
+			}
+		}
+	}
+}
+
 // drawIndicator рисует отдельный индикатор в заданной области
 func (v *Visualizer) drawIndicator(dc *gg.Context, ind IndicatorConfig, topY, bottomY int) {
 	if len(ind.Data) == 0 {
@@ -559,7 +1395,15 @@ func (v *Visualizer) drawIndicator(dc *gg.Context, ind IndicatorConfig, topY, bo
 		}
 
 		// Находим min и max значения для масштабирования
-		minVal, maxVal := v.getIndicatorRange(lineData)
+		minVal, maxVal := v.getIndicatorRange(ind.Type, lineData)
+
+		// Гистограмма строится от нуля, поэтому ноль должен попадать в диапазон
+		style := lineStyle(ind, lineIdx)
+		if style == LineStyleHistogram {
+			minVal = math.Min(minVal, 0)
+			maxVal = math.Max(maxVal, 0)
+		}
+
 		valRange := maxVal - minVal
 
 		if valRange == 0 {
@@ -577,64 +1421,110 @@ func (v *Visualizer) drawIndicator(dc *gg.Context, ind IndicatorConfig, topY, bo
 		dc.SetColor(lineColor)
 		dc.SetLineWidth(ind.LineWidth)
 
-		// Рисуем линию, используя те же X координаты, что и у свечей
-		startX, startY := -1.0, -1.0
-		for i, val := range lineData {
-			if math.IsNaN(val) {
-				startX, startY = -1.0, -1.0
-				continue
+		valueToY := func(val float64) float64 {
+			return float64(topY) + 30 + indicatorHeight - ((val-minVal)/valRange)*indicatorHeight
+		}
+
+		if style == LineStyleHistogram {
+			var barColors []color.Color
+			if lineIdx < len(ind.BarColors) {
+				barColors = ind.BarColors[lineIdx]
 			}
 
-			x := v.candles[i].X // Используем ту же X координату, что и у свечи
-			y := float64(topY) + 30 + indicatorHeight - ((val-minVal)/valRange)*indicatorHeight
+			v.drawHistogram(dc, lineData, lineColor, barColors, valueToY)
+		} else {
+			// Рисуем линию, используя те же X координаты, что и у свечей
+			startX, startY := -1.0, -1.0
+			for i, val := range lineData {
+				if math.IsNaN(val) {
+					startX, startY = -1.0, -1.0
+					continue
+				}
 
-			if startX >= 0 && startY >= 0 {
-				dc.DrawLine(startX, startY, x, y)
-				dc.Stroke()
-			}
+				x := v.candles[i].X // Используем ту же X координату, что и у свечи
+				y := valueToY(val)
 
-			startX, startY = x, y
-		}
+				if startX >= 0 && startY >= 0 {
+					dc.DrawLine(startX, startY, x, y)
+					dc.Stroke()
+				}
 
-		// Для RSI и StochRSI рисуем уровни
-		if ind.Type == IndicatorRSI {
-			dc.SetColor(color.RGBA{150, 150, 150, 100})
-			dc.SetLineWidth(0.5)
+				startX, startY = x, y
+			}
+		}
 
-			// Уровень 30
-			y30 := float64(topY) + 30 + indicatorHeight - ((30-minVal)/valRange)*indicatorHeight
-			dc.DrawLine(float64(v.margin), y30, float64(v.width-v.margin), y30)
+		// Рисуем уровни индикатора (30/70 для RSI, нулевая линия для MACD и т.д.)
+		if lineIdx == 0 {
+			levels := indicatorLevels(ind.Type)
+			if len(levels) > 0 {
+				dc.SetColor(color.RGBA{150, 150, 150, 100})
+				dc.SetLineWidth(0.5)
 
-			// Уровень 70
-			y70 := float64(topY) + 30 + indicatorHeight - ((70-minVal)/valRange)*indicatorHeight
-			dc.DrawLine(float64(v.margin), y70, float64(v.width-v.margin), y70)
+				for _, level := range levels {
+					levelY := valueToY(level)
+					dc.DrawLine(float64(v.margin), levelY, float64(v.width-v.margin), levelY)
+				}
 
-			dc.Stroke()
-		} else if ind.Type == IndicatorStochRSI {
-			dc.SetColor(color.RGBA{150, 150, 150, 100})
-			dc.SetLineWidth(0.5)
+				dc.Stroke()
+			}
+		}
+	}
+}
 
-			// Уровень 20
-			y20 := float64(topY) + 30 + indicatorHeight - ((20-minVal)/valRange)*indicatorHeight
-			dc.DrawLine(float64(v.margin), y20, float64(v.width-v.margin), y20)
+// drawHistogram рисует линию индикатора в виде столбцов от нулевого уровня
+func (v *Visualizer) drawHistogram(dc *gg.Context, data []float64, lineColor color.Color, barColors []color.Color, valueToY func(float64) float64) {
+	zeroY := valueToY(0)
 
-			// Уровень 80
-			y80 := float64(topY) + 30 + indicatorHeight - ((80-minVal)/valRange)*indicatorHeight
-			dc.DrawLine(float64(v.margin), y80, float64(v.width-v.margin), y80)
+	for i, val := range data {
+		if math.IsNaN(val) {
+			continue
+		}
 
-			dc.Stroke()
+		barColor := lineColor
+		if i < len(barColors) && barColors[i] != nil {
+			barColor = barColors[i]
 		}
 
-		// Для MACD рисуем нулевую линию
-		if ind.Type == IndicatorMACD && lineIdx == 0 {
-			dc.SetColor(color.RGBA{150, 150, 150, 100})
-			dc.SetLineWidth(0.5)
+		y := valueToY(val)
+		top := math.Min(y, zeroY)
+		height := math.Max(math.Abs(zeroY-y), 1)
 
-			zeroY := float64(topY) + 30 + indicatorHeight - ((0-minVal)/valRange)*indicatorHeight
-			dc.DrawLine(float64(v.margin), zeroY, float64(v.width-v.margin), zeroY)
-			dc.Stroke()
-		}
+		dc.SetColor(barColor)
+		dc.DrawRectangle(v.candles[i].X-v.candleWidth/2, top, v.candleWidth, height)
+		dc.Fill()
+	}
+}
+
+// lineStyle возвращает стиль отрисовки линии индикатора
+func lineStyle(ind IndicatorConfig, lineIdx int) LineStyle {
+	if lineIdx < len(ind.Styles) && ind.Styles[lineIdx] != "" {
+		return ind.Styles[lineIdx]
+	}
+
+	return LineStyleLine
+}
+
+// indicatorLevels возвращает горизонтальные уровни, которые рисуются на панели индикатора
+func indicatorLevels(t IndicatorType) []float64 {
+	switch t {
+	case IndicatorRSI:
+		return []float64{30, 70}
+	case IndicatorStochRSI:
+		return []float64{20, 80}
+	case IndicatorCMO:
+		return []float64{-50, 0, 50}
+	case IndicatorMFI:
+		return []float64{20, 80}
+	case IndicatorBBPctB:
+		return []float64{0, 1}
+	case IndicatorUO:
+		return []float64{30, 70}
+	case IndicatorMACD, IndicatorROC, IndicatorMomentum, IndicatorTSI, IndicatorAO, IndicatorAC,
+		IndicatorCMF, IndicatorCO, IndicatorFI, IndicatorEMV:
+		return []float64{0}
 	}
+
+	return nil
 }
 
 // drawTitleAndLegend добавляет заголовок и легенду
@@ -717,16 +1607,29 @@ func (v *Visualizer) getPriceRange() (min, max float64) {
 		}
 	}
 
+	for _, level := range v.levels {
+		min = math.Min(min, level.Price)
+		max = math.Max(max, level.Price)
+	}
+
 	// Добавляем небольшой зазор
 	gap := (max - min) * 0.05
 	return min - gap, max + gap
 }
 
-func (v *Visualizer) getIndicatorRange(data []float64) (min, max float64) {
+func (v *Visualizer) getIndicatorRange(t IndicatorType, data []float64) (min, max float64) {
 	if len(data) == 0 {
 		return 0, 1
 	}
 
+	// Для некоторых индикаторов устанавливаем фиксированные диапазоны
+	switch t {
+	case IndicatorRSI, IndicatorStochRSI, IndicatorMFI, IndicatorUO:
+		return 0, 100
+	case IndicatorCMO:
+		return -100, 100
+	}
+
 	min = math.Inf(1)
 	max = math.Inf(-1)
 
@@ -737,16 +1640,6 @@ func (v *Visualizer) getIndicatorRange(data []float64) (min, max float64) {
 		}
 	}
 
-	// Для некоторых индикаторов устанавливаем фиксированные диапазоны
-	for _, ind := range v.indicators {
-		switch ind.Type {
-		case IndicatorRSI:
-			return 0, 100
-		case IndicatorStochRSI:
-			return 0, 100
-		}
-	}
-
 	// Добавляем небольшой зазор
 	gap := (max - min) * 0.1
 	if gap == 0 {
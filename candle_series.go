@@ -1,5 +1,9 @@
 package gota
 
+import (
+	"time"
+)
+
 // CandleSeries - структура для работы массивами свечей
 type CandleSeries []Candle
 
@@ -45,3 +49,49 @@ func (cs CandleSeries) LastMin(nums ...int) CandleSeries {
 
 	return cs.Last(minN)
 }
+
+// NewCandleSeriesFromValues создает серию свечей, у которых все цены равны значениям массива.
+// Позволяет применять индикаторы к произвольным рядам (например, EMA от другого индикатора)
+func NewCandleSeriesFromValues(values []float64) CandleSeries {
+	baseTime := time.Unix(0, 0).UTC()
+	candles := make(CandleSeries, len(values))
+
+	for i, value := range values {
+		candles[i] = NewCandle(
+			baseTime.AddDate(0, 0, i),
+			value, value, value, value, 1.0,
+		)
+	}
+
+	return candles
+}
+
+// ClosePrices возвращает цены закрытия всех свечей серии
+func ClosePrices(series Series) []float64 {
+	result := make([]float64, series.Len())
+	for i := 0; i < series.Len(); i++ {
+		result[i] = series.At(i).GetClosePrice()
+	}
+
+	return result
+}
+
+// HighPrices возвращает максимальные цены всех свечей серии
+func HighPrices(series Series) []float64 {
+	result := make([]float64, series.Len())
+	for i := 0; i < series.Len(); i++ {
+		result[i] = series.At(i).GetHighPrice()
+	}
+
+	return result
+}
+
+// LowPrices возвращает минимальные цены всех свечей серии
+func LowPrices(series Series) []float64 {
+	result := make([]float64, series.Len())
+	for i := 0; i < series.Len(); i++ {
+		result[i] = series.At(i).GetLowPrice()
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package levels
+
+import "github.com/egor-erm/gota"
+
+var (
+	// DefaultRetracementRatios - стандартные уровни коррекции (0 - конец движения, 1 - его начало)
+	DefaultRetracementRatios = []float64{0, 0.236, 0.382, 0.5, 0.618, 0.786, 1}
+	// DefaultExtensionRatios - стандартные уровни расширения движения
+	DefaultExtensionRatios = []float64{1.272, 1.618, 2.0, 2.618}
+)
+
+// FibonacciLevel - ценовой уровень Фибоначчи
+type FibonacciLevel struct {
+	Ratio float64
+	Price float64
+}
+
+// FibonacciResult - уровни Фибоначчи для движения от Start к End
+type FibonacciResult struct {
+	Start        Swing            // Начало движения (A)
+	End          Swing            // Конец движения (B)
+	Retracements []FibonacciLevel // Уровни коррекции: B - (B - A) * ratio
+	Extensions   []FibonacciLevel // Уровни расширения, отложенные от начала: A + (B - A) * ratio
+}
+
+// Fibonacci - автоматические уровни Фибоначчи по последнему движению ZigZag
+// https://www.investopedia.com/terms/f/fibonacciretracement.asp
+type Fibonacci struct {
+	zigzag            *ZigZag
+	retracementRatios []float64
+	extensionRatios   []float64
+}
+
+func NewFibonacci(zigzag *ZigZag) *Fibonacci {
+	return &Fibonacci{
+		zigzag:            zigzag,
+		retracementRatios: DefaultRetracementRatios,
+		extensionRatios:   DefaultExtensionRatios,
+	}
+}
+
+// SetRatios задает уровни коррекции и расширения вместо стандартных
+func (f *Fibonacci) SetRatios(retracements, extensions []float64) {
+	f.retracementRatios = retracements
+	f.extensionRatios = extensions
+}
+
+// Calculate строит уровни по двум последним подтвержденным точкам разворота.
+// Если точек меньше двух, возвращает nil
+func (f Fibonacci) Calculate(series gota.Series) *FibonacciResult {
+	swings := f.zigzag.Calculate(series)
+	if len(swings) < 2 {
+		return nil
+	}
+
+	return f.FromSwings(swings[len(swings)-2], swings[len(swings)-1])
+}
+
+// FromSwings строит уровни для движения от точки start к точке end
+func (f Fibonacci) FromSwings(start, end Swing) *FibonacciResult {
+	move := end.Price - start.Price
+
+	result := &FibonacciResult{
+		Start:        start,
+		End:          end,
+		Retracements: make([]FibonacciLevel, len(f.retracementRatios)),
+		Extensions:   make([]FibonacciLevel, len(f.extensionRatios)),
+	}
+
+	for i, ratio := range f.retracementRatios {
+		result.Retracements[i] = FibonacciLevel{Ratio: ratio, Price: end.Price - move*ratio}
+	}
+
+	for i, ratio := range f.extensionRatios {
+		result.Extensions[i] = FibonacciLevel{Ratio: ratio, Price: start.Price + move*ratio}
+	}
+
+	return result
+}
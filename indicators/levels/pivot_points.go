@@ -0,0 +1,202 @@
+package levels
+
+import (
+	"math"
+	"time"
+
+	"github.com/egor-erm/gota"
+)
+
+// PivotType - способ расчета точек разворота
+type PivotType string
+
+const (
+	PivotClassic   PivotType = "Classic"
+	PivotFibonacci PivotType = "Fibonacci"
+	PivotCamarilla PivotType = "Camarilla"
+	PivotWoodie    PivotType = "Woodie"
+	PivotDeMark    PivotType = "DeMark"
+)
+
+// PivotPeriod - период, по предыдущему значению которого строятся уровни
+type PivotPeriod string
+
+const (
+	PivotDaily   PivotPeriod = "Daily"
+	PivotWeekly  PivotPeriod = "Weekly"
+	PivotMonthly PivotPeriod = "Monthly"
+)
+
+// PivotPoints - точки разворота (pivot points)
+// https://www.investopedia.com/terms/p/pivotpoint.asp
+type PivotPoints struct {
+	pivotType PivotType
+	period    PivotPeriod
+	location  *time.Location // часовой пояс, в котором определяются границы сессий
+}
+
+// PivotPointsResult - уровни для каждой свечи серии. Для свечей первой сессии
+// (нет предыдущего периода) и для уровней, не определенных выбранным методом, значение NaN
+type PivotPointsResult struct {
+	PP []float64 // Точка разворота
+	R1 []float64
+	R2 []float64
+	R3 []float64
+	R4 []float64
+	S1 []float64
+	S2 []float64
+	S3 []float64
+	S4 []float64
+}
+
+// pivotLevels - уровни одного периода
+type pivotLevels struct {
+	pp, r1, r2, r3, r4, s1, s2, s3, s4 float64
+}
+
+func NewPivotPoints(pivotType PivotType, period PivotPeriod, location *time.Location) *PivotPoints {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &PivotPoints{
+		pivotType: pivotType,
+		period:    period,
+		location:  location,
+	}
+}
+
+// Calculate вычисляет уровни каждой свечи по OHLC предыдущего периода
+func (p PivotPoints) Calculate(series gota.Series) *PivotPointsResult {
+	n := series.Len()
+	if n == 0 {
+		return nil
+	}
+
+	result := &PivotPointsResult{
+		PP: nanSlice(n), R1: nanSlice(n), R2: nanSlice(n), R3: nanSlice(n), R4: nanSlice(n),
+		S1: nanSlice(n), S2: nanSlice(n), S3: nanSlice(n), S4: nanSlice(n),
+	}
+
+	var open, high, low, close float64
+	var levels *pivotLevels
+	currentKey := p.sessionKey(series.At(0).GetStartTime())
+
+	for i := 0; i < n; i++ {
+		candle := series.At(i)
+		key := p.sessionKey(candle.GetStartTime())
+
+		if i == 0 || key != currentKey {
+			// Завершилась сессия - считаем уровни для новой по ее OHLC
+			if i > 0 {
+				l := p.calculateLevels(open, high, low, close)
+				levels = &l
+			}
+
+			currentKey = key
+			open = candle.GetOpenPrice()
+			high = candle.GetHighPrice()
+			low = candle.GetLowPrice()
+		}
+
+		high = math.Max(high, candle.GetHighPrice())
+		low = math.Min(low, candle.GetLowPrice())
+		close = candle.GetClosePrice()
+
+		if levels != nil {
+			result.PP[i] = levels.pp
+			result.R1[i], result.R2[i], result.R3[i], result.R4[i] = levels.r1, levels.r2, levels.r3, levels.r4
+			result.S1[i], result.S2[i], result.S3[i], result.S4[i] = levels.s1, levels.s2, levels.s3, levels.s4
+		}
+	}
+
+	return result
+}
+
+// sessionKey возвращает идентификатор сессии, к которой относится время
+func (p PivotPoints) sessionKey(t time.Time) int {
+	t = t.In(p.location)
+
+	switch p.period {
+	case PivotWeekly:
+		year, week := t.ISOWeek()
+		return year*100 + week
+	case PivotMonthly:
+		return t.Year()*100 + int(t.Month())
+	default:
+		return t.Year()*1000 + t.YearDay()
+	}
+}
+
+// calculateLevels вычисляет уровни по OHLC завершенного периода
+func (p PivotPoints) calculateLevels(open, high, low, close float64) pivotLevels {
+	rng := high - low
+	l := pivotLevels{r4: math.NaN(), s4: math.NaN()}
+
+	switch p.pivotType {
+	case PivotFibonacci:
+		l.pp = (high + low + close) / 3
+		l.r1 = l.pp + 0.382*rng
+		l.r2 = l.pp + 0.618*rng
+		l.r3 = l.pp + rng
+		l.s1 = l.pp - 0.382*rng
+		l.s2 = l.pp - 0.618*rng
+		l.s3 = l.pp - rng
+
+	case PivotCamarilla:
+		l.pp = (high + low + close) / 3
+		l.r1 = close + rng*1.1/12
+		l.r2 = close + rng*1.1/6
+		l.r3 = close + rng*1.1/4
+		l.r4 = close + rng*1.1/2
+		l.s1 = close - rng*1.1/12
+		l.s2 = close - rng*1.1/6
+		l.s3 = close - rng*1.1/4
+		l.s4 = close - rng*1.1/2
+
+	case PivotWoodie:
+		l.pp = (high + low + 2*close) / 4
+		l.r1 = 2*l.pp - low
+		l.r2 = l.pp + rng
+		l.r3 = high + 2*(l.pp-low)
+		l.s1 = 2*l.pp - high
+		l.s2 = l.pp - rng
+		l.s3 = low - 2*(high-l.pp)
+
+	case PivotDeMark:
+		var x float64
+		switch {
+		case close < open:
+			x = high + 2*low + close
+		case close > open:
+			x = 2*high + low + close
+		default:
+			x = high + low + 2*close
+		}
+
+		l.pp = x / 4
+		l.r1 = x/2 - low
+		l.s1 = x/2 - high
+		l.r2, l.r3, l.s2, l.s3 = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+
+	default:
+		l.pp = (high + low + close) / 3
+		l.r1 = 2*l.pp - low
+		l.r2 = l.pp + rng
+		l.r3 = high + 2*(l.pp-low)
+		l.s1 = 2*l.pp - high
+		l.s2 = l.pp - rng
+		l.s3 = low - 2*(high-l.pp)
+	}
+
+	return l
+}
+
+func nanSlice(n int) []float64 {
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = math.NaN()
+	}
+
+	return result
+}
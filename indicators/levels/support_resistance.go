@@ -0,0 +1,124 @@
+package levels
+
+import (
+	"math"
+	"sort"
+
+	"github.com/egor-erm/gota"
+)
+
+// Level - горизонтальный уровень, образованный группой близких точек разворота
+type Level struct {
+	Price      float64 // Средняя цена точек разворота уровня
+	Touches    int     // Количество точек разворота (касаний)
+	Strength   float64 // Сила уровня: сумма весов касаний, вес растет от 0 у начала серии до 1 у последней свечи
+	FirstIndex int     // Индекс первого касания
+	LastIndex  int     // Индекс последнего касания
+	Swings     []Swing // Точки разворота, образовавшие уровень
+}
+
+// SupportResistanceResult - уровни относительно последней цены закрытия
+type SupportResistanceResult struct {
+	Price      float64 // Последняя цена закрытия
+	Support    []Level // Уровни ниже цены, от ближайшего к дальнему
+	Resistance []Level // Уровни выше цены, от ближайшего к дальнему
+}
+
+// SupportResistance - автоматические уровни поддержки и сопротивления.
+// Точки разворота ZigZag группируются в уровни, если их цены отличаются от средней цены
+// группы не более чем на tolerance процентов
+type SupportResistance struct {
+	zigzag     *ZigZag
+	tolerance  float64
+	minTouches int
+}
+
+func NewSupportResistance(zigzag *ZigZag, tolerance float64, minTouches int) *SupportResistance {
+	return &SupportResistance{
+		zigzag:     zigzag,
+		tolerance:  tolerance,
+		minTouches: minTouches,
+	}
+}
+
+func (sr SupportResistance) Calculate(series gota.Series) *SupportResistanceResult {
+	if series.Len() == 0 {
+		return nil
+	}
+
+	result := &SupportResistanceResult{
+		Price:      series.At(series.Len() - 1).GetClosePrice(),
+		Support:    make([]Level, 0),
+		Resistance: make([]Level, 0),
+	}
+
+	for _, level := range sr.cluster(sr.zigzag.Calculate(series), series.Len()) {
+		if level.Touches < sr.minTouches {
+			continue
+		}
+
+		if level.Price < result.Price {
+			result.Support = append(result.Support, level)
+		} else {
+			result.Resistance = append(result.Resistance, level)
+		}
+	}
+
+	// Уровни отсортированы по возрастанию цены - поддержки разворачиваем, чтобы ближайшая была первой
+	for i, j := 0, len(result.Support)-1; i < j; i, j = i+1, j-1 {
+		result.Support[i], result.Support[j] = result.Support[j], result.Support[i]
+	}
+
+	return result
+}
+
+// cluster группирует точки разворота по цене и возвращает уровни по возрастанию цены
+func (sr SupportResistance) cluster(swings []Swing, length int) []Level {
+	if len(swings) == 0 {
+		return nil
+	}
+
+	sorted := make([]Swing, len(swings))
+	copy(sorted, swings)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Price < sorted[j].Price })
+
+	levels := make([]Level, 0)
+	group := []Swing{sorted[0]}
+	sum := sorted[0].Price
+
+	for _, swing := range sorted[1:] {
+		mean := sum / float64(len(group))
+		if math.Abs(swing.Price-mean) <= mean*sr.tolerance/100 {
+			group = append(group, swing)
+			sum += swing.Price
+			continue
+		}
+
+		levels = append(levels, newLevel(group, length))
+		group = []Swing{swing}
+		sum = swing.Price
+	}
+
+	return append(levels, newLevel(group, length))
+}
+
+// newLevel собирает уровень из группы точек разворота
+func newLevel(group []Swing, length int) Level {
+	sort.Slice(group, func(i, j int) bool { return group[i].Index < group[j].Index })
+
+	level := Level{
+		Touches:    len(group),
+		FirstIndex: group[0].Index,
+		LastIndex:  group[len(group)-1].Index,
+		Swings:     group,
+	}
+
+	for _, swing := range group {
+		level.Price += swing.Price
+		// Более свежие касания весят больше
+		level.Strength += float64(swing.Index+1) / float64(length)
+	}
+	level.Price /= float64(len(group))
+
+	return level
+}
@@ -0,0 +1,143 @@
+package levels
+
+import (
+	"time"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/volatility"
+)
+
+// SwingType - тип точки разворота
+type SwingType int
+
+const (
+	// SwingHigh - локальный максимум
+	SwingHigh SwingType = iota
+	// SwingLow - локальный минимум
+	SwingLow
+)
+
+// Swing - подтвержденная точка разворота ZigZag
+type Swing struct {
+	Index int       // Индекс свечи экстремума
+	Time  time.Time // Время начала свечи экстремума
+	Price float64   // High для максимума, Low для минимума
+	Type  SwingType // Тип точки
+}
+
+// ZigZag - индикатор ZigZag. Экстремум считается точкой разворота, когда цена
+// отходит от него на заданный порог: процент от цены экстремума или multiplier * ATR
+// https://www.investopedia.com/terms/z/zig_zag_indicator.asp
+type ZigZag struct {
+	percent       float64
+	atrPeriod     int
+	atrMultiplier float64
+}
+
+// NewZigZag создает ZigZag с порогом разворота в процентах от цены экстремума
+func NewZigZag(percent float64) *ZigZag {
+	return &ZigZag{percent: percent}
+}
+
+// NewZigZagATR создает ZigZag с порогом разворота multiplier * ATR(period)
+func NewZigZagATR(period int, multiplier float64) *ZigZag {
+	return &ZigZag{
+		atrPeriod:     period,
+		atrMultiplier: multiplier,
+	}
+}
+
+// Calculate возвращает подтвержденные точки разворота в порядке возрастания индекса.
+// Максимумы и минимумы чередуются. Последний, еще не подтвержденный экстремум не возвращается
+func (z ZigZag) Calculate(series gota.Series) []Swing {
+	if series.Len() < 2 {
+		return nil
+	}
+
+	thresholds := z.thresholds(series)
+	if thresholds == nil {
+		return nil
+	}
+
+	swings := make([]Swing, 0)
+	newSwing := func(index int, swingType SwingType) Swing {
+		candle := series.At(index)
+		price := candle.GetHighPrice()
+		if swingType == SwingLow {
+			price = candle.GetLowPrice()
+		}
+
+		return Swing{Index: index, Time: candle.GetStartTime(), Price: price, Type: swingType}
+	}
+
+	// direction: 0 - направление еще не определено, 1 - движение вверх, -1 - вниз
+	direction := 0
+	highIdx, lowIdx := 0, 0
+
+	for i := 1; i < series.Len(); i++ {
+		candle := series.At(i)
+		high := candle.GetHighPrice()
+		low := candle.GetLowPrice()
+		highPrice := series.At(highIdx).GetHighPrice()
+		lowPrice := series.At(lowIdx).GetLowPrice()
+
+		switch direction {
+		case 0:
+			// До первого разворота отслеживаем оба экстремума
+			if high > highPrice {
+				highIdx = i
+			}
+			if low < lowPrice {
+				lowIdx = i
+			}
+
+			if highIdx < i && highPrice-low >= z.threshold(thresholds[i], highPrice) {
+				swings = append(swings, newSwing(highIdx, SwingHigh))
+				direction, lowIdx = -1, i
+			} else if lowIdx < i && high-lowPrice >= z.threshold(thresholds[i], lowPrice) {
+				swings = append(swings, newSwing(lowIdx, SwingLow))
+				direction, highIdx = 1, i
+			}
+
+		case 1:
+			if high > highPrice {
+				highIdx = i
+			} else if highPrice-low >= z.threshold(thresholds[i], highPrice) {
+				swings = append(swings, newSwing(highIdx, SwingHigh))
+				direction, lowIdx = -1, i
+			}
+
+		case -1:
+			if low < lowPrice {
+				lowIdx = i
+			} else if high-lowPrice >= z.threshold(thresholds[i], lowPrice) {
+				swings = append(swings, newSwing(lowIdx, SwingLow))
+				direction, highIdx = 1, i
+			}
+		}
+	}
+
+	return swings
+}
+
+// thresholds возвращает ATR для каждой свечи серии (NaN, пока ATR не определен).
+// Для процентного порога возвращает срез нулей
+func (z ZigZag) thresholds(series gota.Series) []float64 {
+	if z.atrPeriod <= 0 {
+		return make([]float64, series.Len())
+	}
+
+	atr, _ := volatility.AlignedATR(series, z.atrPeriod)
+
+	return atr
+}
+
+// threshold возвращает минимальное расстояние разворота от экстремума price
+func (z ZigZag) threshold(atr, price float64) float64 {
+	if z.atrPeriod > 0 {
+		// Пока ATR не определен, сравнение с NaN всегда ложно и разворот не подтверждается
+		return z.atrMultiplier * atr
+	}
+
+	return price * z.percent / 100
+}
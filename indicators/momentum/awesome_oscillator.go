@@ -0,0 +1,82 @@
+package momentum
+
+import (
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/trend"
+	"github.com/egor-erm/gota/utils"
+)
+
+// AwesomeOscillator - Awesome Oscillator Билла Вильямса
+// https://www.investopedia.com/terms/a/awesomeoscillator.asp
+type AwesomeOscillator struct {
+	fastPeriod int
+	slowPeriod int
+}
+
+func NewAwesomeOscillator(fastPeriod, slowPeriod int) *AwesomeOscillator {
+	return &AwesomeOscillator{
+		fastPeriod: fastPeriod,
+		slowPeriod: slowPeriod,
+	}
+}
+
+// Calculate вычисляет AO как разницу быстрой и медленной SMA от медианной цены (High + Low) / 2
+func (a AwesomeOscillator) Calculate(series gota.Series) []float64 {
+	if series.Len() < a.slowPeriod || series.Len() < a.fastPeriod {
+		return nil
+	}
+
+	medianPrices := make([]float64, series.Len())
+	for i := 0; i < series.Len(); i++ {
+		candle := series.At(i)
+		medianPrices[i] = (candle.GetHighPrice() + candle.GetLowPrice()) / 2
+	}
+
+	medianSeries := gota.NewCandleSeriesFromValues(medianPrices)
+	fastSMA := trend.NewSMA(a.fastPeriod).Calculate(medianSeries)
+	slowSMA := trend.NewSMA(a.slowPeriod).Calculate(medianSeries)
+
+	// Выравниваем длины (SMA начинаются с разных индексов)
+	fastSMA, slowSMA = utils.AlignLengths(fastSMA, slowSMA)
+
+	result := make([]float64, len(slowSMA))
+	for i := range slowSMA {
+		result[i] = fastSMA[i] - slowSMA[i]
+	}
+
+	return result
+}
+
+// AcceleratorOscillator - Accelerator/Decelerator Oscillator Билла Вильямса
+// https://www.investopedia.com/terms/a/acceleration-deceleration-indicator.asp
+type AcceleratorOscillator struct {
+	fastPeriod   int
+	slowPeriod   int
+	signalPeriod int
+}
+
+func NewAcceleratorOscillator(fastPeriod, slowPeriod, signalPeriod int) *AcceleratorOscillator {
+	return &AcceleratorOscillator{
+		fastPeriod:   fastPeriod,
+		slowPeriod:   slowPeriod,
+		signalPeriod: signalPeriod,
+	}
+}
+
+// Calculate вычисляет AC как разницу AO и его SMA с периодом signalPeriod
+func (a AcceleratorOscillator) Calculate(series gota.Series) []float64 {
+	ao := NewAwesomeOscillator(a.fastPeriod, a.slowPeriod).Calculate(series)
+	if len(ao) < a.signalPeriod {
+		return nil
+	}
+
+	aoSMA := trend.NewSMA(a.signalPeriod).Calculate(gota.NewCandleSeriesFromValues(ao))
+	ao, aoSMA = utils.AlignLengths(ao, aoSMA)
+
+	result := make([]float64, len(aoSMA))
+	for i := range aoSMA {
+		result[i] = ao[i] - aoSMA[i]
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package momentum
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// CMO - Chande Momentum Oscillator
+// https://www.investopedia.com/terms/c/chandemomentumoscillator.asp
+type CMO struct {
+	period int
+}
+
+func NewCMO(period int) *CMO {
+	return &CMO{period: period}
+}
+
+func (c CMO) Period() int {
+	return c.period
+}
+
+// Calculate вычисляет CMO в диапазоне от -100 до 100
+func (c CMO) Calculate(series gota.Series) []float64 {
+	if c.period <= 0 || series.Len() <= c.period {
+		return nil
+	}
+
+	// Изменения цены закрытия (начиная со второй свечи)
+	changes := make([]float64, series.Len())
+	for i := 1; i < series.Len(); i++ {
+		changes[i] = series.At(i).GetClosePrice() - series.At(i-1).GetClosePrice()
+	}
+
+	result := make([]float64, 0, series.Len()-c.period)
+
+	for i := c.period; i < series.Len(); i++ {
+		sumUp := 0.0
+		sumDown := 0.0
+
+		for j := 0; j < c.period; j++ {
+			change := changes[i-j]
+			if change > 0 {
+				sumUp += change
+			} else {
+				sumDown -= change
+			}
+		}
+
+		if sumUp+sumDown == 0 {
+			result = append(result, 0)
+			continue
+		}
+
+		result = append(result, 100*(sumUp-sumDown)/(sumUp+sumDown))
+	}
+
+	return result
+}
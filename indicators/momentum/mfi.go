@@ -0,0 +1,74 @@
+package momentum
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// MFI - Money Flow Index
+// https://www.investopedia.com/terms/m/mfi.asp
+type MFI struct {
+	period int
+}
+
+func NewMFI(period int) *MFI {
+	return &MFI{period: period}
+}
+
+func (m MFI) Period() int {
+	return m.period
+}
+
+// Calculate вычисляет MFI - аналог RSI, взвешенный по объему (диапазон от 0 до 100)
+func (m MFI) Calculate(series gota.Series) []float64 {
+	if m.period <= 0 || series.Len() <= m.period {
+		return nil
+	}
+
+	// Типичная цена и положительный/отрицательный денежный поток
+	positiveFlow := make([]float64, series.Len())
+	negativeFlow := make([]float64, series.Len())
+
+	prevTypical := typicalPrice(series.At(0))
+	for i := 1; i < series.Len(); i++ {
+		candle := series.At(i)
+		typical := typicalPrice(candle)
+		rawFlow := typical * candle.GetVolume()
+
+		if typical > prevTypical {
+			positiveFlow[i] = rawFlow
+		} else if typical < prevTypical {
+			negativeFlow[i] = rawFlow
+		}
+
+		prevTypical = typical
+	}
+
+	result := make([]float64, 0, series.Len()-m.period)
+
+	for i := m.period; i < series.Len(); i++ {
+		sumPositive := 0.0
+		sumNegative := 0.0
+
+		for j := 0; j < m.period; j++ {
+			sumPositive += positiveFlow[i-j]
+			sumNegative += negativeFlow[i-j]
+		}
+
+		switch {
+		case sumNegative == 0 && sumPositive == 0:
+			result = append(result, 50.0)
+		case sumNegative == 0:
+			result = append(result, 100.0)
+		default:
+			moneyRatio := sumPositive / sumNegative
+			result = append(result, 100.0-(100.0/(1.0+moneyRatio)))
+		}
+	}
+
+	return result
+}
+
+// typicalPrice вычисляет типичную цену свечи (High + Low + Close) / 3
+func typicalPrice(candle gota.Candle) float64 {
+	return (candle.GetHighPrice() + candle.GetLowPrice() + candle.GetClosePrice()) / 3
+}
@@ -0,0 +1,33 @@
+package momentum
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// Momentum - разница между текущей ценой закрытия и ценой закрытия period свечей назад
+// https://www.investopedia.com/terms/m/momentum.asp
+type Momentum struct {
+	period int
+}
+
+func NewMomentum(period int) *Momentum {
+	return &Momentum{period: period}
+}
+
+func (m Momentum) Period() int {
+	return m.period
+}
+
+func (m Momentum) Calculate(series gota.Series) []float64 {
+	if m.period <= 0 || series.Len() <= m.period {
+		return nil
+	}
+
+	result := make([]float64, 0, series.Len()-m.period)
+
+	for i := m.period; i < series.Len(); i++ {
+		result = append(result, series.At(i).GetClosePrice()-series.At(i-m.period).GetClosePrice())
+	}
+
+	return result
+}
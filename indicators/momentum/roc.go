@@ -0,0 +1,40 @@
+package momentum
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// ROC - Rate of Change
+// https://www.investopedia.com/terms/p/pricerateofchange.asp
+type ROC struct {
+	period int
+}
+
+func NewROC(period int) *ROC {
+	return &ROC{period: period}
+}
+
+func (r ROC) Period() int {
+	return r.period
+}
+
+// Calculate вычисляет процентное изменение цены закрытия относительно свечи period назад
+func (r ROC) Calculate(series gota.Series) []float64 {
+	if r.period <= 0 || series.Len() <= r.period {
+		return nil
+	}
+
+	result := make([]float64, 0, series.Len()-r.period)
+
+	for i := r.period; i < series.Len(); i++ {
+		prevClose := series.At(i - r.period).GetClosePrice()
+		if prevClose == 0 {
+			result = append(result, 0)
+			continue
+		}
+
+		result = append(result, 100*(series.At(i).GetClosePrice()-prevClose)/prevClose)
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+package momentum
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/trend"
+	"github.com/egor-erm/gota/utils"
+)
+
+// TSI - True Strength Index
+// https://www.investopedia.com/terms/t/tsi.asp
+type TSI struct {
+	longPeriod   int
+	shortPeriod  int
+	signalPeriod int
+}
+
+type TSIResult struct {
+	TSI    []float64 // Основная линия TSI
+	Signal []float64 // Сигнальная линия (EMA от TSI)
+}
+
+func NewTSI(longPeriod, shortPeriod, signalPeriod int) *TSI {
+	return &TSI{
+		longPeriod:   longPeriod,
+		shortPeriod:  shortPeriod,
+		signalPeriod: signalPeriod,
+	}
+}
+
+// Calculate вычисляет TSI как отношение дважды сглаженного изменения цены
+// к дважды сглаженному модулю изменения цены
+func (t TSI) Calculate(series gota.Series) *TSIResult {
+	if series.Len() < t.longPeriod+t.shortPeriod {
+		return nil
+	}
+
+	changes := make([]float64, 0, series.Len()-1)
+	absChanges := make([]float64, 0, series.Len()-1)
+
+	for i := 1; i < series.Len(); i++ {
+		change := series.At(i).GetClosePrice() - series.At(i-1).GetClosePrice()
+		changes = append(changes, change)
+		absChanges = append(absChanges, math.Abs(change))
+	}
+
+	smoothed := t.doubleSmooth(changes)
+	absSmoothed := t.doubleSmooth(absChanges)
+	if len(smoothed) == 0 || len(absSmoothed) == 0 {
+		return nil
+	}
+
+	tsi := make([]float64, len(smoothed))
+	for i := range smoothed {
+		if absSmoothed[i] != 0 {
+			tsi[i] = 100 * smoothed[i] / absSmoothed[i]
+		}
+	}
+
+	signal := trend.NewEMA(t.signalPeriod).Calculate(gota.NewCandleSeriesFromValues(tsi))
+	if len(signal) == 0 {
+		return nil
+	}
+
+	// Выравниваем длины (сигнальная линия начинается позже)
+	tsi, signal = utils.AlignLengths(tsi, signal)
+
+	return &TSIResult{
+		TSI:    tsi,
+		Signal: signal,
+	}
+}
+
+// doubleSmooth применяет к ряду EMA с длинным периодом, а затем EMA с коротким
+func (t TSI) doubleSmooth(values []float64) []float64 {
+	first := trend.NewEMA(t.longPeriod).Calculate(gota.NewCandleSeriesFromValues(values))
+	if len(first) == 0 {
+		return nil
+	}
+
+	return trend.NewEMA(t.shortPeriod).Calculate(gota.NewCandleSeriesFromValues(first))
+}
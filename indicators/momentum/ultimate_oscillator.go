@@ -0,0 +1,73 @@
+package momentum
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+)
+
+// UltimateOscillator - Ultimate Oscillator Ларри Вильямса
+// https://www.investopedia.com/terms/u/ultimateoscillator.asp
+type UltimateOscillator struct {
+	shortPeriod  int
+	mediumPeriod int
+	longPeriod   int
+}
+
+func NewUltimateOscillator(shortPeriod, mediumPeriod, longPeriod int) *UltimateOscillator {
+	return &UltimateOscillator{
+		shortPeriod:  shortPeriod,
+		mediumPeriod: mediumPeriod,
+		longPeriod:   longPeriod,
+	}
+}
+
+// Calculate вычисляет Ultimate Oscillator по трем таймфреймам с весами 4:2:1
+func (u UltimateOscillator) Calculate(series gota.Series) []float64 {
+	maxPeriod := max(u.shortPeriod, u.mediumPeriod, u.longPeriod)
+	if u.shortPeriod <= 0 || series.Len() <= maxPeriod {
+		return nil
+	}
+
+	// Buying Pressure и True Range для каждой свечи (начиная со второй)
+	buyingPressure := make([]float64, series.Len())
+	trueRange := make([]float64, series.Len())
+
+	for i := 1; i < series.Len(); i++ {
+		curr := series.At(i)
+		prevClose := series.At(i - 1).GetClosePrice()
+
+		trueLow := math.Min(curr.GetLowPrice(), prevClose)
+		trueHigh := math.Max(curr.GetHighPrice(), prevClose)
+
+		buyingPressure[i] = curr.GetClosePrice() - trueLow
+		trueRange[i] = trueHigh - trueLow
+	}
+
+	average := func(i, period int) float64 {
+		sumBP := 0.0
+		sumTR := 0.0
+		for j := 0; j < period; j++ {
+			sumBP += buyingPressure[i-j]
+			sumTR += trueRange[i-j]
+		}
+
+		if sumTR == 0 {
+			return 0
+		}
+
+		return sumBP / sumTR
+	}
+
+	result := make([]float64, 0, series.Len()-maxPeriod)
+
+	for i := maxPeriod; i < series.Len(); i++ {
+		avgShort := average(i, u.shortPeriod)
+		avgMedium := average(i, u.mediumPeriod)
+		avgLong := average(i, u.longPeriod)
+
+		result = append(result, 100*(4*avgShort+2*avgMedium+avgLong)/7)
+	}
+
+	return result
+}
@@ -0,0 +1,392 @@
+package patterns
+
+import (
+	"math"
+	"time"
+
+	"github.com/egor-erm/gota"
+)
+
+// PatternType - тип свечной формации
+type PatternType string
+
+const (
+	PatternDoji          PatternType = "Doji"
+	PatternHammer        PatternType = "Hammer"
+	PatternShootingStar  PatternType = "ShootingStar"
+	PatternEngulfing     PatternType = "Engulfing"
+	PatternHarami        PatternType = "Harami"
+	PatternMorningStar   PatternType = "MorningStar"
+	PatternEveningStar   PatternType = "EveningStar"
+	PatternThreeSoldiers PatternType = "ThreeWhiteSoldiers"
+	PatternThreeCrows    PatternType = "ThreeBlackCrows"
+	PatternPiercing      PatternType = "PiercingLine"
+	PatternDarkCloud     PatternType = "DarkCloudCover"
+)
+
+// ShortName возвращает короткое обозначение формации для подписей на графике
+func (t PatternType) ShortName() string {
+	switch t {
+	case PatternDoji:
+		return "D"
+	case PatternHammer:
+		return "H"
+	case PatternShootingStar:
+		return "SS"
+	case PatternEngulfing:
+		return "E"
+	case PatternHarami:
+		return "Hr"
+	case PatternMorningStar:
+		return "MS"
+	case PatternEveningStar:
+		return "ES"
+	case PatternThreeSoldiers:
+		return "3WS"
+	case PatternThreeCrows:
+		return "3BC"
+	case PatternPiercing:
+		return "P"
+	case PatternDarkCloud:
+		return "DC"
+	}
+
+	return string(t)
+}
+
+// Bias - направленность формации
+type Bias int
+
+const (
+	// Neutral - формация нерешительности
+	Neutral Bias = iota
+	// Bullish - бычья формация
+	Bullish
+	// Bearish - медвежья формация
+	Bearish
+)
+
+// PatternEvent - найденная формация
+type PatternEvent struct {
+	Index int         // Индекс последней свечи формации
+	Time  time.Time   // Время начала последней свечи формации
+	Type  PatternType // Тип формации
+	Bias  Bias        // Направленность формации
+}
+
+// Config - пороги распознавания формаций (доли от диапазона High-Low свечи или от тела)
+type Config struct {
+	DojiBodyRatio    float64 // Максимальное тело доджи относительно диапазона свечи
+	SmallBodyRatio   float64 // Максимальное "маленькое" тело (звезды, молот) относительно диапазона
+	LongBodyRatio    float64 // Минимальное "длинное" тело относительно диапазона
+	LongShadowRatio  float64 // Минимальная длинная тень относительно тела (молот, падающая звезда)
+	ShortShadowRatio float64 // Максимальная короткая тень относительно диапазона свечи
+
+	SoldiersBodyRatio   float64 // Минимальное тело свечей трех белых солдат/трех черных ворон относительно диапазона
+	SoldiersShadowRatio float64 // Максимальная тень по направлению движения у трех солдат/трех ворон относительно диапазона
+
+	TrendPeriod int // Количество свечей для определения предшествующего тренда (0 - не проверять)
+}
+
+// DefaultConfig возвращает пороги распознавания по умолчанию
+func DefaultConfig() Config {
+	return Config{
+		DojiBodyRatio:    0.1,
+		SmallBodyRatio:   0.3,
+		LongBodyRatio:    0.6,
+		LongShadowRatio:  2.0,
+		ShortShadowRatio: 0.1,
+
+		SoldiersBodyRatio:   0.5,
+		SoldiersShadowRatio: 0.2,
+
+		TrendPeriod: 5,
+	}
+}
+
+// CandlestickPatterns - распознавание свечных формаций
+type CandlestickPatterns struct {
+	config Config
+}
+
+func NewCandlestickPatterns(config Config) *CandlestickPatterns {
+	return &CandlestickPatterns{config: config}
+}
+
+// Calculate находит все формации в серии. На одной свече может быть найдено несколько формаций
+func (cp CandlestickPatterns) Calculate(series gota.Series) []PatternEvent {
+	var events []PatternEvent
+
+	for i := 0; i < series.Len(); i++ {
+		add := func(t PatternType, bias Bias) {
+			events = append(events, PatternEvent{
+				Index: i,
+				Time:  series.At(i).GetStartTime(),
+				Type:  t,
+				Bias:  bias,
+			})
+		}
+
+		curr := newCandleShape(series.At(i))
+
+		// Односвечные формации
+		if cp.isDoji(curr) {
+			add(PatternDoji, Neutral)
+		}
+
+		if cp.isHammer(curr) && cp.trendBefore(series, i) <= 0 {
+			add(PatternHammer, Bullish)
+		}
+
+		if cp.isShootingStar(curr) && cp.trendBefore(series, i) >= 0 {
+			add(PatternShootingStar, Bearish)
+		}
+
+		// Двухсвечные формации
+		if i >= 1 {
+			prev := newCandleShape(series.At(i - 1))
+			trend := cp.trendBefore(series, i-1)
+
+			if bias := cp.engulfing(prev, curr); bias != Neutral && cp.trendAllows(trend, bias) {
+				add(PatternEngulfing, bias)
+			}
+
+			if bias := cp.harami(prev, curr); bias != Neutral && cp.trendAllows(trend, bias) {
+				add(PatternHarami, bias)
+			}
+
+			if cp.isPiercing(prev, curr) && trend <= 0 {
+				add(PatternPiercing, Bullish)
+			}
+
+			if cp.isDarkCloud(prev, curr) && trend >= 0 {
+				add(PatternDarkCloud, Bearish)
+			}
+		}
+
+		// Трехсвечные формации
+		if i >= 2 {
+			first := newCandleShape(series.At(i - 2))
+			second := newCandleShape(series.At(i - 1))
+			trend := cp.trendBefore(series, i-2)
+
+			if cp.isMorningStar(first, second, curr) && trend <= 0 {
+				add(PatternMorningStar, Bullish)
+			}
+
+			if cp.isEveningStar(first, second, curr) && trend >= 0 {
+				add(PatternEveningStar, Bearish)
+			}
+
+			if cp.isThreeSoldiers(first, second, curr) {
+				add(PatternThreeSoldiers, Bullish)
+			}
+
+			if cp.isThreeCrows(first, second, curr) {
+				add(PatternThreeCrows, Bearish)
+			}
+		}
+	}
+
+	return events
+}
+
+// candleShape - геометрия свечи
+type candleShape struct {
+	open, high, low, close float64
+	body                   float64 // модуль тела
+	rng                    float64 // диапазон High-Low
+	upperShadow            float64
+	lowerShadow            float64
+}
+
+func newCandleShape(c gota.Candle) candleShape {
+	open, close := c.GetOpenPrice(), c.GetClosePrice()
+
+	return candleShape{
+		open:        open,
+		high:        c.GetHighPrice(),
+		low:         c.GetLowPrice(),
+		close:       close,
+		body:        math.Abs(close - open),
+		rng:         c.GetHighPrice() - c.GetLowPrice(),
+		upperShadow: c.GetHighPrice() - math.Max(open, close),
+		lowerShadow: math.Min(open, close) - c.GetLowPrice(),
+	}
+}
+
+func (s candleShape) bullish() bool {
+	return s.close > s.open
+}
+
+func (s candleShape) bearish() bool {
+	return s.close < s.open
+}
+
+func (s candleShape) bodyTop() float64 {
+	return math.Max(s.open, s.close)
+}
+
+func (s candleShape) bodyBottom() float64 {
+	return math.Min(s.open, s.close)
+}
+
+func (s candleShape) bodyMid() float64 {
+	return (s.open + s.close) / 2
+}
+
+func (cp CandlestickPatterns) isLong(s candleShape) bool {
+	return s.rng > 0 && s.body >= cp.config.LongBodyRatio*s.rng
+}
+
+// isSoldier проверяет свечу трех солдат/трех ворон: длинное тело и короткая тень shadow по направлению движения
+func (cp CandlestickPatterns) isSoldier(s candleShape, shadow float64) bool {
+	return s.rng > 0 && s.body >= cp.config.SoldiersBodyRatio*s.rng && shadow <= cp.config.SoldiersShadowRatio*s.rng
+}
+
+func (cp CandlestickPatterns) isSmall(s candleShape) bool {
+	return s.rng > 0 && s.body <= cp.config.SmallBodyRatio*s.rng
+}
+
+func (cp CandlestickPatterns) isDoji(s candleShape) bool {
+	return s.rng > 0 && s.body <= cp.config.DojiBodyRatio*s.rng
+}
+
+func (cp CandlestickPatterns) isHammer(s candleShape) bool {
+	return cp.isSmall(s) && !cp.isDoji(s) &&
+		s.lowerShadow >= cp.config.LongShadowRatio*s.body &&
+		s.upperShadow <= cp.config.ShortShadowRatio*s.rng
+}
+
+func (cp CandlestickPatterns) isShootingStar(s candleShape) bool {
+	return cp.isSmall(s) && !cp.isDoji(s) &&
+		s.upperShadow >= cp.config.LongShadowRatio*s.body &&
+		s.lowerShadow <= cp.config.ShortShadowRatio*s.rng
+}
+
+// engulfing - тело второй свечи противоположного цвета полностью поглощает тело первой
+func (cp CandlestickPatterns) engulfing(prev, curr candleShape) Bias {
+	if curr.body <= prev.body {
+		return Neutral
+	}
+
+	if prev.bearish() && curr.bullish() && curr.open <= prev.close && curr.close >= prev.open {
+		return Bullish
+	}
+
+	if prev.bullish() && curr.bearish() && curr.open >= prev.close && curr.close <= prev.open {
+		return Bearish
+	}
+
+	return Neutral
+}
+
+// harami - маленькое тело второй свечи находится внутри длинного тела первой
+func (cp CandlestickPatterns) harami(prev, curr candleShape) Bias {
+	if !cp.isLong(prev) || curr.body >= prev.body ||
+		curr.bodyTop() > prev.bodyTop() || curr.bodyBottom() < prev.bodyBottom() {
+		return Neutral
+	}
+
+	if prev.bearish() && curr.bullish() {
+		return Bullish
+	}
+
+	if prev.bullish() && curr.bearish() {
+		return Bearish
+	}
+
+	return Neutral
+}
+
+func (cp CandlestickPatterns) isPiercing(prev, curr candleShape) bool {
+	return cp.isLong(prev) && prev.bearish() && curr.bullish() &&
+		curr.open < prev.close && curr.close > prev.bodyMid() && curr.close < prev.open
+}
+
+func (cp CandlestickPatterns) isDarkCloud(prev, curr candleShape) bool {
+	return cp.isLong(prev) && prev.bullish() && curr.bearish() &&
+		curr.open > prev.close && curr.close < prev.bodyMid() && curr.close > prev.open
+}
+
+func (cp CandlestickPatterns) isMorningStar(first, second, third candleShape) bool {
+	return cp.isLong(first) && first.bearish() &&
+		cp.isSmall(second) && second.bodyTop() < first.bodyMid() &&
+		third.bullish() && third.close > first.bodyMid()
+}
+
+func (cp CandlestickPatterns) isEveningStar(first, second, third candleShape) bool {
+	return cp.isLong(first) && first.bullish() &&
+		cp.isSmall(second) && second.bodyBottom() > first.bodyMid() &&
+		third.bearish() && third.close < first.bodyMid()
+}
+
+// isThreeSoldiers - три растущие свечи с длинными телами, каждая открывается внутри тела предыдущей
+// и закрывается выше нее
+func (cp CandlestickPatterns) isThreeSoldiers(first, second, third candleShape) bool {
+	candles := []candleShape{first, second, third}
+	for i, c := range candles {
+		if !c.bullish() || !cp.isSoldier(c, c.upperShadow) {
+			return false
+		}
+
+		if i > 0 {
+			prev := candles[i-1]
+			if c.close <= prev.close || c.open < prev.open || c.open > prev.close {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// isThreeCrows - три падающие свечи с длинными телами, каждая открывается внутри тела предыдущей
+// и закрывается ниже нее
+func (cp CandlestickPatterns) isThreeCrows(first, second, third candleShape) bool {
+	candles := []candleShape{first, second, third}
+	for i, c := range candles {
+		if !c.bearish() || !cp.isSoldier(c, c.lowerShadow) {
+			return false
+		}
+
+		if i > 0 {
+			prev := candles[i-1]
+			if c.close >= prev.close || c.open > prev.open || c.open < prev.close {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// trendBefore определяет тренд перед свечой index: 1 - восходящий, -1 - нисходящий, 0 - не определен.
+// Если проверка тренда отключена или свечей недостаточно, возвращает 0 (подходит любой формации)
+func (cp CandlestickPatterns) trendBefore(series gota.Series, index int) int {
+	period := cp.config.TrendPeriod
+	if period <= 0 || index-1-period < 0 {
+		return 0
+	}
+
+	last := series.At(index - 1).GetClosePrice()
+	first := series.At(index - 1 - period).GetClosePrice()
+
+	switch {
+	case last > first:
+		return 1
+	case last < first:
+		return -1
+	}
+
+	return 0
+}
+
+// trendAllows проверяет, что разворотная формация появилась после тренда противоположного направления
+func (cp CandlestickPatterns) trendAllows(trend int, bias Bias) bool {
+	if bias == Bullish {
+		return trend <= 0
+	}
+
+	return trend >= 0
+}
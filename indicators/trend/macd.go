@@ -1,7 +1,6 @@
 package trend
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/egor-erm/gota"
@@ -38,7 +37,6 @@ func (m MACD) Calculate(series gota.Series) *MACDResult {
 	// Вычисляем EMA для быстрой и медленной линии
 	fastEMA := NewEMA(m.fastPeriod).Calculate(series)
 	slowEMA := NewEMA(m.slowPeriod).Calculate(series)
-	fmt.Println(fastEMA, slowEMA)
 
 	// Выравниваем длины (EMA начинаются с разных индексов)
 	fastEMA, slowEMA = utils.AlignLengths(fastEMA, slowEMA)
@@ -0,0 +1,32 @@
+package trend
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// MAType - тип скользящей средней
+type MAType string
+
+const (
+	MATypeSMA MAType = "SMA"
+	MATypeEMA MAType = "EMA"
+	MATypeWMA MAType = "WMA"
+)
+
+// MovingAverage - общий интерфейс скользящих средних
+type MovingAverage interface {
+	Period() int
+	Calculate(series gota.Series) []float64
+}
+
+// NewMovingAverage создает скользящую среднюю заданного типа (по умолчанию SMA)
+func NewMovingAverage(maType MAType, period int) MovingAverage {
+	switch maType {
+	case MATypeEMA:
+		return NewEMA(period)
+	case MATypeWMA:
+		return NewWMA(period)
+	default:
+		return NewSMA(period)
+	}
+}
@@ -55,3 +55,21 @@ func (a ATR) Calculate(series gota.Series) []float64 {
 
 	return result
 }
+
+// AlignedATR вычисляет ATR, выровненный по свечам серии: значения до first равны NaN.
+// Если ATR не определен (серия короче периода), возвращает nil
+func AlignedATR(series gota.Series, period int) (atr []float64, first int) {
+	values := NewATR(period).Calculate(series)
+	if len(values) == 0 {
+		return nil, 0
+	}
+
+	first = series.Len() - len(values)
+	atr = make([]float64, series.Len())
+	for i := 0; i < first; i++ {
+		atr[i] = math.NaN()
+	}
+	copy(atr[first:], values)
+
+	return atr, first
+}
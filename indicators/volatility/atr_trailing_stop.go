@@ -0,0 +1,68 @@
+package volatility
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+)
+
+// ATRTrailingStop - трейлинг-стоп на расстоянии multiplier * ATR от цены закрытия.
+// Стоп двигается только в сторону позиции и переворачивается, когда цена закрывается за ним
+type ATRTrailingStop struct {
+	period     int
+	multiplier float64
+}
+
+type ATRTrailingStopResult struct {
+	Stop      []float64 // Уровень стопа
+	Direction []int     // Направление: 1 - стоп под ценой (длинная позиция), -1 - над ценой (короткая)
+}
+
+func NewATRTrailingStop(period int, multiplier float64) *ATRTrailingStop {
+	return &ATRTrailingStop{
+		period:     period,
+		multiplier: multiplier,
+	}
+}
+
+func (ts ATRTrailingStop) Period() int {
+	return ts.period
+}
+
+func (ts ATRTrailingStop) Calculate(series gota.Series) *ATRTrailingStopResult {
+	atr, first := AlignedATR(series, ts.period)
+	if atr == nil {
+		return nil
+	}
+
+	stop := make([]float64, len(atr)-first)
+	direction := make([]int, len(atr)-first)
+
+	// Начинаем с длинной позиции
+	stop[0] = series.At(first).GetClosePrice() - ts.multiplier*atr[first]
+	direction[0] = 1
+
+	for k := 1; k < len(stop); k++ {
+		close := series.At(first + k).GetClosePrice()
+		prevClose := series.At(first + k - 1).GetClosePrice()
+		prevStop := stop[k-1]
+		loss := ts.multiplier * atr[first+k]
+
+		switch {
+		case close > prevStop && prevClose > prevStop:
+			stop[k] = math.Max(prevStop, close-loss)
+			direction[k] = 1
+		case close < prevStop && prevClose < prevStop:
+			stop[k] = math.Min(prevStop, close+loss)
+			direction[k] = -1
+		case close > prevStop:
+			stop[k] = close - loss
+			direction[k] = 1
+		default:
+			stop[k] = close + loss
+			direction[k] = -1
+		}
+	}
+
+	return &ATRTrailingStopResult{stop, direction}
+}
@@ -1,31 +1,47 @@
 package volatility
 
 import (
-	"math"
-
 	"github.com/egor-erm/gota"
 	"github.com/egor-erm/gota/indicators/trend"
+	"github.com/egor-erm/gota/utils"
 )
 
 // Bollinger Bands
 type BollingerBands struct {
-	period int
-	stdDev float64
+	period       int
+	stdDev       float64
+	maType       trend.MAType // тип средней линии (по умолчанию SMA)
+	sampleStdDev bool         // выборочное (n-1) вместо генерального (n) стандартного отклонения
 }
 
 type BollingerBandsResult struct {
 	UpperBand  []float64
 	MiddleBand []float64
 	LowerBand  []float64
+	PercentB   []float64 // %B - положение цены закрытия относительно полос (0 - нижняя, 1 - верхняя)
+	Bandwidth  []float64 // Ширина полос относительно средней линии: (Upper - Lower) / Middle
 }
 
 func NewBollingerBands(period int, stdDev float64) *BollingerBands {
 	return &BollingerBands{
 		period: period,
 		stdDev: stdDev,
+		maType: trend.MATypeSMA,
 	}
 }
 
+// SetMAType устанавливает тип скользящей средней для средней линии
+func (bb *BollingerBands) SetMAType(maType trend.MAType) {
+	bb.maType = maType
+}
+
+// SetSampleStdDev включает выборочное стандартное отклонение (деление на period-1)
+func (bb *BollingerBands) SetSampleStdDev(sample bool) {
+	bb.sampleStdDev = sample
+}
+
+// Calculate вычисляет полосы. Стандартное отклонение всегда считается относительно
+// среднего значения окна, а тип скользящей средней влияет только на среднюю линию
 func (bb BollingerBands) Calculate(series gota.Series) *BollingerBandsResult {
 	if series.Len() < bb.period {
 		return nil
@@ -34,25 +50,40 @@ func (bb BollingerBands) Calculate(series gota.Series) *BollingerBandsResult {
 	upperBand := make([]float64, 0)
 	middleBand := make([]float64, 0)
 	lowerBand := make([]float64, 0)
+	percentB := make([]float64, 0)
+	bandwidth := make([]float64, 0)
+
+	ma := trend.NewMovingAverage(bb.maType, bb.period)
+	maValues := ma.Calculate(series)
 
-	sma := trend.NewSMA(bb.period)
-	smaValues := sma.Calculate(series)
+	stdDevs := utils.RollingStdDev(gota.ClosePrices(series), bb.period, bb.sampleStdDev)
+	if len(stdDevs) == 0 {
+		return nil
+	}
 
 	for i := bb.period - 1; i < series.Len(); i++ {
-		sumSquares := 0.0
-		smaValue := smaValues[i-bb.period+1]
+		stdDev := stdDevs[i-bb.period+1]
+		maValue := maValues[i-bb.period+1]
 
-		for j := 0; j < bb.period; j++ {
-			diff := series.At(i-j).GetClosePrice() - smaValue
-			sumSquares += diff * diff
-		}
+		upper := maValue + bb.stdDev*stdDev
+		lower := maValue - bb.stdDev*stdDev
 
-		stdDev := math.Sqrt(sumSquares / float64(bb.period))
+		middleBand = append(middleBand, maValue)
+		upperBand = append(upperBand, upper)
+		lowerBand = append(lowerBand, lower)
 
-		middleBand = append(middleBand, smaValue)
-		upperBand = append(upperBand, smaValue+bb.stdDev*stdDev)
-		lowerBand = append(lowerBand, smaValue-bb.stdDev*stdDev)
+		if upper != lower {
+			percentB = append(percentB, (series.At(i).GetClosePrice()-lower)/(upper-lower))
+		} else {
+			percentB = append(percentB, 0.5)
+		}
+
+		if maValue != 0 {
+			bandwidth = append(bandwidth, (upper-lower)/maValue)
+		} else {
+			bandwidth = append(bandwidth, 0)
+		}
 	}
 
-	return &BollingerBandsResult{upperBand, middleBand, lowerBand}
+	return &BollingerBandsResult{upperBand, middleBand, lowerBand, percentB, bandwidth}
 }
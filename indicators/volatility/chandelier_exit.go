@@ -0,0 +1,56 @@
+package volatility
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+)
+
+// ChandelierExit - стоп-линии Chandelier Exit Чарльза Ле Бо
+type ChandelierExit struct {
+	period     int
+	multiplier float64
+}
+
+type ChandelierExitResult struct {
+	LongStop  []float64 // Максимальный High за период минус multiplier * ATR
+	ShortStop []float64 // Минимальный Low за период плюс multiplier * ATR
+}
+
+func NewChandelierExit(period int, multiplier float64) *ChandelierExit {
+	return &ChandelierExit{
+		period:     period,
+		multiplier: multiplier,
+	}
+}
+
+func (ce ChandelierExit) Period() int {
+	return ce.period
+}
+
+func (ce ChandelierExit) Calculate(series gota.Series) *ChandelierExitResult {
+	atr, first := AlignedATR(series, ce.period)
+	if atr == nil {
+		return nil
+	}
+
+	longStop := make([]float64, len(atr)-first)
+	shortStop := make([]float64, len(atr)-first)
+
+	for k := range longStop {
+		i := first + k
+
+		highest := math.Inf(-1)
+		lowest := math.Inf(1)
+		for j := 0; j < ce.period && i-j >= 0; j++ {
+			candle := series.At(i - j)
+			highest = math.Max(highest, candle.GetHighPrice())
+			lowest = math.Min(lowest, candle.GetLowPrice())
+		}
+
+		longStop[k] = highest - ce.multiplier*atr[i]
+		shortStop[k] = lowest + ce.multiplier*atr[i]
+	}
+
+	return &ChandelierExitResult{longStop, shortStop}
+}
@@ -0,0 +1,54 @@
+package volatility
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+)
+
+// DonchianChannels - каналы Дончиана
+// https://www.investopedia.com/terms/d/donchianchannels.asp
+type DonchianChannels struct {
+	period int
+}
+
+type DonchianChannelsResult struct {
+	UpperBand  []float64 // Максимальный High за период
+	MiddleBand []float64 // Середина канала
+	LowerBand  []float64 // Минимальный Low за период
+}
+
+func NewDonchianChannels(period int) *DonchianChannels {
+	return &DonchianChannels{period: period}
+}
+
+func (dc DonchianChannels) Period() int {
+	return dc.period
+}
+
+func (dc DonchianChannels) Calculate(series gota.Series) *DonchianChannelsResult {
+	if dc.period <= 0 || series.Len() < dc.period {
+		return nil
+	}
+
+	upperBand := make([]float64, 0, series.Len()-dc.period+1)
+	middleBand := make([]float64, 0, series.Len()-dc.period+1)
+	lowerBand := make([]float64, 0, series.Len()-dc.period+1)
+
+	for i := dc.period - 1; i < series.Len(); i++ {
+		highest := math.Inf(-1)
+		lowest := math.Inf(1)
+
+		for j := 0; j < dc.period; j++ {
+			candle := series.At(i - j)
+			highest = math.Max(highest, candle.GetHighPrice())
+			lowest = math.Min(lowest, candle.GetLowPrice())
+		}
+
+		upperBand = append(upperBand, highest)
+		middleBand = append(middleBand, (highest+lowest)/2)
+		lowerBand = append(lowerBand, lowest)
+	}
+
+	return &DonchianChannelsResult{upperBand, middleBand, lowerBand}
+}
@@ -0,0 +1,168 @@
+package volatility
+
+import (
+	"math"
+	"time"
+
+	"github.com/egor-erm/gota"
+)
+
+// HVEstimator - способ оценки исторической волатильности
+type HVEstimator string
+
+const (
+	// EstimatorCloseToClose - стандартное отклонение логарифмических доходностей close-to-close
+	EstimatorCloseToClose HVEstimator = "CloseToClose"
+	// EstimatorParkinson - оценка Паркинсона по диапазону High-Low
+	EstimatorParkinson HVEstimator = "Parkinson"
+	// EstimatorGarmanKlass - оценка Гармана-Класса по OHLC
+	EstimatorGarmanKlass HVEstimator = "GarmanKlass"
+	// EstimatorRogersSatchell - оценка Роджерса-Сатчелла (устойчива к тренду)
+	EstimatorRogersSatchell HVEstimator = "RogersSatchell"
+	// EstimatorYangZhang - оценка Янга-Чжана (учитывает гэпы между свечами)
+	EstimatorYangZhang HVEstimator = "YangZhang"
+)
+
+// HistoricalVolatility - годовая реализованная волатильность
+type HistoricalVolatility struct {
+	period         int
+	estimator      HVEstimator
+	periodsPerYear float64 // количество свечей в году для приведения к годовому значению
+}
+
+func NewHistoricalVolatility(period int, estimator HVEstimator, periodsPerYear float64) *HistoricalVolatility {
+	return &HistoricalVolatility{
+		period:         period,
+		estimator:      estimator,
+		periodsPerYear: periodsPerYear,
+	}
+}
+
+func (hv HistoricalVolatility) Period() int {
+	return hv.period
+}
+
+// Calculate вычисляет годовую волатильность в долях (0.25 = 25%) по скользящему окну из period свечей.
+// Первое значение соответствует свече с индексом period, так как часть оценок использует предыдущее закрытие
+func (hv HistoricalVolatility) Calculate(series gota.Series) []float64 {
+	if hv.period < 2 || series.Len() <= hv.period || hv.periodsPerYear <= 0 {
+		return nil
+	}
+
+	result := make([]float64, 0, series.Len()-hv.period)
+
+	for i := hv.period; i < series.Len(); i++ {
+		variance := hv.variance(series, i-hv.period+1, i)
+		result = append(result, math.Sqrt(math.Max(variance, 0)*hv.periodsPerYear))
+	}
+
+	return result
+}
+
+// variance вычисляет дисперсию за одну свечу по окну [start, end]
+func (hv HistoricalVolatility) variance(series gota.Series, start, end int) float64 {
+	n := float64(end - start + 1)
+
+	switch hv.estimator {
+	case EstimatorParkinson:
+		sum := 0.0
+		for i := start; i <= end; i++ {
+			c := series.At(i)
+			hl := math.Log(c.GetHighPrice() / c.GetLowPrice())
+			sum += hl * hl
+		}
+		return sum / (4 * math.Ln2 * n)
+
+	case EstimatorGarmanKlass:
+		sum := 0.0
+		for i := start; i <= end; i++ {
+			c := series.At(i)
+			hl := math.Log(c.GetHighPrice() / c.GetLowPrice())
+			co := math.Log(c.GetClosePrice() / c.GetOpenPrice())
+			sum += 0.5*hl*hl - (2*math.Ln2-1)*co*co
+		}
+		return sum / n
+
+	case EstimatorRogersSatchell:
+		return rogersSatchellVariance(series, start, end)
+
+	case EstimatorYangZhang:
+		overnight := make([]float64, 0, end-start+1)
+		openToClose := make([]float64, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			c := series.At(i)
+			overnight = append(overnight, math.Log(c.GetOpenPrice()/series.At(i-1).GetClosePrice()))
+			openToClose = append(openToClose, math.Log(c.GetClosePrice()/c.GetOpenPrice()))
+		}
+
+		k := 0.34 / (1.34 + (n+1)/(n-1))
+		return sampleVariance(overnight) + k*sampleVariance(openToClose) + (1-k)*rogersSatchellVariance(series, start, end)
+
+	default:
+		returns := make([]float64, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			returns = append(returns, math.Log(series.At(i).GetClosePrice()/series.At(i-1).GetClosePrice()))
+		}
+		return sampleVariance(returns)
+	}
+}
+
+// rogersSatchellVariance вычисляет среднюю дисперсию Роджерса-Сатчелла по окну [start, end]
+func rogersSatchellVariance(series gota.Series, start, end int) float64 {
+	sum := 0.0
+	for i := start; i <= end; i++ {
+		c := series.At(i)
+		hc := math.Log(c.GetHighPrice() / c.GetClosePrice())
+		ho := math.Log(c.GetHighPrice() / c.GetOpenPrice())
+		lc := math.Log(c.GetLowPrice() / c.GetClosePrice())
+		lo := math.Log(c.GetLowPrice() / c.GetOpenPrice())
+		sum += hc*ho + lc*lo
+	}
+
+	return sum / float64(end-start+1)
+}
+
+// sampleVariance вычисляет выборочную дисперсию (деление на n-1)
+func sampleVariance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	sumSquares := 0.0
+	for _, v := range values {
+		sumSquares += (v - mean) * (v - mean)
+	}
+
+	return sumSquares / float64(len(values)-1)
+}
+
+// PeriodsPerYear вычисляет количество свечей таймфрейма в году для приведения волатильности к годовой.
+// Для дневных свечей возвращает tradingDays (например 252 для акций или 365 для криптовалют),
+// для внутридневных - tradingDays * sessionLength / timeframe (sessionLength - длительность торговой сессии),
+// для свечей старше дня - количество свечей в календарном году
+func PeriodsPerYear(timeframe time.Duration, tradingDays float64, sessionLength time.Duration) float64 {
+	const day = 24 * time.Hour
+
+	if timeframe <= 0 {
+		return 0
+	}
+
+	if sessionLength <= 0 || sessionLength > day {
+		sessionLength = day
+	}
+
+	switch {
+	case timeframe == day:
+		return tradingDays
+	case timeframe > day:
+		return 365.25 * float64(day) / float64(timeframe)
+	default:
+		return tradingDays * float64(sessionLength) / float64(timeframe)
+	}
+}
@@ -0,0 +1,50 @@
+package volatility
+
+import (
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/trend"
+	"github.com/egor-erm/gota/utils"
+)
+
+// KeltnerChannels - каналы Кельтнера
+// https://www.investopedia.com/terms/k/keltnerchannel.asp
+type KeltnerChannels struct {
+	emaPeriod  int
+	atrPeriod  int
+	multiplier float64
+}
+
+type KeltnerChannelsResult struct {
+	UpperBand  []float64
+	MiddleBand []float64
+	LowerBand  []float64
+}
+
+func NewKeltnerChannels(emaPeriod, atrPeriod int, multiplier float64) *KeltnerChannels {
+	return &KeltnerChannels{
+		emaPeriod:  emaPeriod,
+		atrPeriod:  atrPeriod,
+		multiplier: multiplier,
+	}
+}
+
+// Calculate вычисляет среднюю линию как EMA от цены закрытия, а границы - как EMA ± multiplier * ATR
+func (kc KeltnerChannels) Calculate(series gota.Series) *KeltnerChannelsResult {
+	ema := trend.NewEMA(kc.emaPeriod).Calculate(series)
+	atr := NewATR(kc.atrPeriod).Calculate(series)
+	if len(ema) == 0 || len(atr) == 0 {
+		return nil
+	}
+
+	// Выравниваем длины (EMA и ATR начинаются с разных индексов)
+	ema, atr = utils.AlignLengths(ema, atr)
+
+	upperBand := make([]float64, len(ema))
+	lowerBand := make([]float64, len(ema))
+	for i := range ema {
+		upperBand[i] = ema[i] + kc.multiplier*atr[i]
+		lowerBand[i] = ema[i] - kc.multiplier*atr[i]
+	}
+
+	return &KeltnerChannelsResult{upperBand, ema, lowerBand}
+}
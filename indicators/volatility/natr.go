@@ -0,0 +1,35 @@
+package volatility
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// NATR - Normalized Average True Range (ATR в процентах от цены закрытия)
+type NATR struct {
+	period int
+}
+
+func NewNATR(period int) *NATR {
+	return &NATR{period: period}
+}
+
+func (n NATR) Period() int {
+	return n.period
+}
+
+func (n NATR) Calculate(series gota.Series) []float64 {
+	atr, first := AlignedATR(series, n.period)
+	if atr == nil {
+		return nil
+	}
+
+	result := make([]float64, len(atr)-first)
+	for i := first; i < len(atr); i++ {
+		close := series.At(i).GetClosePrice()
+		if close != 0 {
+			result[i-first] = 100 * atr[i] / close
+		}
+	}
+
+	return result
+}
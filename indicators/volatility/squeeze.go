@@ -0,0 +1,23 @@
+package volatility
+
+import (
+	"github.com/egor-erm/gota/utils"
+)
+
+// DetectSqueeze определяет "сжатие" волатильности: полосы Боллинджера находятся внутри каналов Кельтнера.
+// Результат выровнен по последним значениям обоих индикаторов
+func DetectSqueeze(bb *BollingerBandsResult, kc *KeltnerChannelsResult) []bool {
+	if bb == nil || kc == nil {
+		return nil
+	}
+
+	aligned := utils.AlignLengthsMulti(bb.UpperBand, bb.LowerBand, kc.UpperBand, kc.LowerBand)
+	bbUpper, bbLower, kcUpper, kcLower := aligned[0], aligned[1], aligned[2], aligned[3]
+
+	result := make([]bool, len(bbUpper))
+	for i := range bbUpper {
+		result[i] = bbUpper[i] < kcUpper[i] && bbLower[i] > kcLower[i]
+	}
+
+	return result
+}
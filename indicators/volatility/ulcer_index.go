@@ -0,0 +1,51 @@
+package volatility
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/utils"
+)
+
+// UlcerIndex - индекс язвы Питера Мартина (глубина и продолжительность просадок)
+type UlcerIndex struct {
+	period int
+}
+
+func NewUlcerIndex(period int) *UlcerIndex {
+	return &UlcerIndex{period: period}
+}
+
+func (u UlcerIndex) Period() int {
+	return u.period
+}
+
+// Calculate вычисляет среднеквадратичную процентную просадку цены закрытия
+// от максимума за period свечей
+func (u UlcerIndex) Calculate(series gota.Series) []float64 {
+	if u.period <= 0 || series.Len() < 2*u.period-1 {
+		return nil
+	}
+
+	closes := gota.ClosePrices(series)
+	maxCloses := utils.RollingMax(closes, u.period)
+
+	// Процентная просадка от максимума (maxCloses выровнен по последним ценам)
+	offset := len(closes) - len(maxCloses)
+	squaredDrawdowns := make([]float64, len(maxCloses))
+	for i, maxClose := range maxCloses {
+		if maxClose != 0 {
+			drawdown := 100 * (closes[offset+i] - maxClose) / maxClose
+			squaredDrawdowns[i] = drawdown * drawdown
+		}
+	}
+
+	meanSquares := utils.RollingMean(squaredDrawdowns, u.period)
+
+	result := make([]float64, len(meanSquares))
+	for i, value := range meanSquares {
+		result[i] = math.Sqrt(value)
+	}
+
+	return result
+}
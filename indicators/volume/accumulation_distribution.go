@@ -0,0 +1,48 @@
+package volume
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// AccumulationDistribution - линия накопления/распределения (A/D line)
+// https://www.investopedia.com/terms/a/accumulationdistribution.asp
+type AccumulationDistribution struct{}
+
+func NewAccumulationDistribution() *AccumulationDistribution {
+	return &AccumulationDistribution{}
+}
+
+// Calculate вычисляет накопленную сумму денежного потока (Money Flow Volume)
+func (a AccumulationDistribution) Calculate(series gota.Series) []float64 {
+	if series.Len() == 0 {
+		return nil
+	}
+
+	result := make([]float64, series.Len())
+
+	sum := 0.0
+	for i := 0; i < series.Len(); i++ {
+		sum += moneyFlowVolume(series.At(i))
+		result[i] = sum
+	}
+
+	return result
+}
+
+// moneyFlowMultiplier вычисляет положение цены закрытия внутри диапазона свечи (от -1 до 1)
+func moneyFlowMultiplier(candle gota.Candle) float64 {
+	high := candle.GetHighPrice()
+	low := candle.GetLowPrice()
+
+	if high == low {
+		return 0
+	}
+
+	close := candle.GetClosePrice()
+	return ((close - low) - (high - close)) / (high - low)
+}
+
+// moneyFlowVolume вычисляет денежный поток свечи (множитель, умноженный на объем)
+func moneyFlowVolume(candle gota.Candle) float64 {
+	return moneyFlowMultiplier(candle) * candle.GetVolume()
+}
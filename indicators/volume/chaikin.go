@@ -0,0 +1,85 @@
+package volume
+
+import (
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/trend"
+	"github.com/egor-erm/gota/utils"
+)
+
+// ChaikinMoneyFlow - денежный поток Чайкина
+// https://www.investopedia.com/terms/c/chaikinoscillator.asp
+type ChaikinMoneyFlow struct {
+	period int
+}
+
+func NewChaikinMoneyFlow(period int) *ChaikinMoneyFlow {
+	return &ChaikinMoneyFlow{period: period}
+}
+
+func (c ChaikinMoneyFlow) Period() int {
+	return c.period
+}
+
+// Calculate вычисляет CMF как отношение суммы денежного потока к сумме объема за период (от -1 до 1)
+func (c ChaikinMoneyFlow) Calculate(series gota.Series) []float64 {
+	if c.period <= 0 || series.Len() < c.period {
+		return nil
+	}
+
+	result := make([]float64, 0, series.Len()-c.period+1)
+
+	for i := c.period - 1; i < series.Len(); i++ {
+		sumFlow := 0.0
+		sumVolume := 0.0
+
+		for j := 0; j < c.period; j++ {
+			candle := series.At(i - j)
+			sumFlow += moneyFlowVolume(candle)
+			sumVolume += candle.GetVolume()
+		}
+
+		if sumVolume == 0 {
+			result = append(result, 0)
+			continue
+		}
+
+		result = append(result, sumFlow/sumVolume)
+	}
+
+	return result
+}
+
+// ChaikinOscillator - осциллятор Чайкина (разница быстрой и медленной EMA от линии A/D)
+type ChaikinOscillator struct {
+	fastPeriod int
+	slowPeriod int
+}
+
+func NewChaikinOscillator(fastPeriod, slowPeriod int) *ChaikinOscillator {
+	return &ChaikinOscillator{
+		fastPeriod: fastPeriod,
+		slowPeriod: slowPeriod,
+	}
+}
+
+func (c ChaikinOscillator) Calculate(series gota.Series) []float64 {
+	if series.Len() < c.slowPeriod || series.Len() < c.fastPeriod {
+		return nil
+	}
+
+	adLine := NewAccumulationDistribution().Calculate(series)
+	adSeries := gota.NewCandleSeriesFromValues(adLine)
+
+	fastEMA := trend.NewEMA(c.fastPeriod).Calculate(adSeries)
+	slowEMA := trend.NewEMA(c.slowPeriod).Calculate(adSeries)
+
+	// Выравниваем длины (EMA начинаются с разных индексов)
+	fastEMA, slowEMA = utils.AlignLengths(fastEMA, slowEMA)
+
+	result := make([]float64, len(slowEMA))
+	for i := range slowEMA {
+		result[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	return result
+}
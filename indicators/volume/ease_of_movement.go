@@ -0,0 +1,54 @@
+package volume
+
+import (
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/trend"
+)
+
+// EaseOfMovement - индикатор легкости движения (EMV) Ричарда Армса
+// https://www.investopedia.com/terms/e/easeofmovement.asp
+type EaseOfMovement struct {
+	period  int
+	divisor float64 // масштаб объема, например 100000000
+}
+
+func NewEaseOfMovement(period int, divisor float64) *EaseOfMovement {
+	return &EaseOfMovement{
+		period:  period,
+		divisor: divisor,
+	}
+}
+
+func (e EaseOfMovement) Period() int {
+	return e.period
+}
+
+// Calculate вычисляет SMA от однодневного значения EMV
+func (e EaseOfMovement) Calculate(series gota.Series) []float64 {
+	if series.Len() <= e.period || e.divisor == 0 {
+		return nil
+	}
+
+	emv := make([]float64, 0, series.Len()-1)
+	for i := 1; i < series.Len(); i++ {
+		curr := series.At(i)
+		prev := series.At(i - 1)
+
+		distance := (curr.GetHighPrice()+curr.GetLowPrice())/2 - (prev.GetHighPrice()+prev.GetLowPrice())/2
+		priceRange := curr.GetHighPrice() - curr.GetLowPrice()
+
+		if priceRange == 0 || curr.GetVolume() == 0 {
+			emv = append(emv, 0)
+			continue
+		}
+
+		boxRatio := (curr.GetVolume() / e.divisor) / priceRange
+		emv = append(emv, distance/boxRatio)
+	}
+
+	if e.period <= 1 {
+		return emv
+	}
+
+	return trend.NewSMA(e.period).Calculate(gota.NewCandleSeriesFromValues(emv))
+}
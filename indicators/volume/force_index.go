@@ -0,0 +1,41 @@
+package volume
+
+import (
+	"github.com/egor-erm/gota"
+	"github.com/egor-erm/gota/indicators/trend"
+)
+
+// ForceIndex - индекс силы Элдера
+// https://www.investopedia.com/terms/f/force-index.asp
+type ForceIndex struct {
+	period int
+}
+
+func NewForceIndex(period int) *ForceIndex {
+	return &ForceIndex{period: period}
+}
+
+func (f ForceIndex) Period() int {
+	return f.period
+}
+
+// Calculate вычисляет EMA от произведения изменения цены закрытия на объем
+func (f ForceIndex) Calculate(series gota.Series) []float64 {
+	if series.Len() <= f.period {
+		return nil
+	}
+
+	rawForce := make([]float64, 0, series.Len()-1)
+	for i := 1; i < series.Len(); i++ {
+		candle := series.At(i)
+		change := candle.GetClosePrice() - series.At(i-1).GetClosePrice()
+		rawForce = append(rawForce, change*candle.GetVolume())
+	}
+
+	// При периоде 1 индекс силы не сглаживается
+	if f.period <= 1 {
+		return rawForce
+	}
+
+	return trend.NewEMA(f.period).Calculate(gota.NewCandleSeriesFromValues(rawForce))
+}
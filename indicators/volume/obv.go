@@ -0,0 +1,38 @@
+package volume
+
+import (
+	"github.com/egor-erm/gota"
+)
+
+// OBV - On-Balance Volume
+// https://www.investopedia.com/terms/o/onbalancevolume.asp
+type OBV struct{}
+
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+// Calculate вычисляет накопленный объем: объем прибавляется при росте цены закрытия и вычитается при падении
+func (o OBV) Calculate(series gota.Series) []float64 {
+	if series.Len() == 0 {
+		return nil
+	}
+
+	result := make([]float64, series.Len())
+
+	for i := 1; i < series.Len(); i++ {
+		close := series.At(i).GetClosePrice()
+		prevClose := series.At(i - 1).GetClosePrice()
+
+		switch {
+		case close > prevClose:
+			result[i] = result[i-1] + series.At(i).GetVolume()
+		case close < prevClose:
+			result[i] = result[i-1] - series.At(i).GetVolume()
+		default:
+			result[i] = result[i-1]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,145 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/egor-erm/gota"
+)
+
+// VolumeProfile - профиль объема (распределение объема по ценовым уровням)
+// https://www.investopedia.com/terms/v/volume-analysis.asp
+type VolumeProfile struct {
+	bins      int     // количество ценовых корзин
+	valueArea float64 // доля объема в зоне стоимости, например 0.7 = 70%
+}
+
+// VolumeProfileLevel - ценовая корзина профиля
+type VolumeProfileLevel struct {
+	PriceLow  float64 // Нижняя граница корзины
+	PriceHigh float64 // Верхняя граница корзины
+	Volume    float64 // Объем, пришедшийся на корзину
+}
+
+// Price возвращает середину ценовой корзины
+func (l VolumeProfileLevel) Price() float64 {
+	return (l.PriceLow + l.PriceHigh) / 2
+}
+
+type VolumeProfileResult struct {
+	Levels             []VolumeProfileLevel // Корзины от нижней цены к верхней
+	POC                float64              // Point of Control - цена с максимальным объемом
+	POCIndex           int                  // Индекс корзины POC в Levels
+	ValueAreaHigh      float64              // Верхняя граница зоны стоимости
+	ValueAreaLow       float64              // Нижняя граница зоны стоимости
+	ValueAreaLowIndex  int                  // Индекс нижней корзины зоны стоимости
+	ValueAreaHighIndex int                  // Индекс верхней корзины зоны стоимости
+	TotalVolume        float64              // Общий объем за окно
+}
+
+func NewVolumeProfile(bins int, valueArea float64) *VolumeProfile {
+	return &VolumeProfile{
+		bins:      bins,
+		valueArea: valueArea,
+	}
+}
+
+// Calculate строит профиль объема по всем свечам серии.
+// Объем каждой свечи распределяется равномерно по ее диапазону High-Low
+func (vp VolumeProfile) Calculate(series gota.Series) *VolumeProfileResult {
+	if series.Len() == 0 || vp.bins <= 0 {
+		return nil
+	}
+
+	// Находим ценовой диапазон окна
+	minPrice := math.Inf(1)
+	maxPrice := math.Inf(-1)
+	for i := 0; i < series.Len(); i++ {
+		candle := series.At(i)
+		minPrice = math.Min(minPrice, candle.GetLowPrice())
+		maxPrice = math.Max(maxPrice, candle.GetHighPrice())
+	}
+
+	if maxPrice == minPrice {
+		maxPrice = minPrice + 1
+	}
+
+	binSize := (maxPrice - minPrice) / float64(vp.bins)
+	levels := make([]VolumeProfileLevel, vp.bins)
+	for i := range levels {
+		levels[i].PriceLow = minPrice + float64(i)*binSize
+		levels[i].PriceHigh = minPrice + float64(i+1)*binSize
+	}
+
+	binIndex := func(price float64) int {
+		idx := int((price - minPrice) / binSize)
+		return min(max(idx, 0), vp.bins-1)
+	}
+
+	totalVolume := 0.0
+	for i := 0; i < series.Len(); i++ {
+		candle := series.At(i)
+		low := candle.GetLowPrice()
+		high := candle.GetHighPrice()
+		vol := candle.GetVolume()
+		totalVolume += vol
+
+		// Свеча без диапазона - весь объем в одну корзину
+		if high <= low {
+			levels[binIndex(candle.GetClosePrice())].Volume += vol
+			continue
+		}
+
+		// Распределяем объем пропорционально пересечению диапазона свечи с корзиной
+		for b := binIndex(low); b <= binIndex(high); b++ {
+			overlap := math.Min(high, levels[b].PriceHigh) - math.Max(low, levels[b].PriceLow)
+			if overlap > 0 {
+				levels[b].Volume += vol * overlap / (high - low)
+			}
+		}
+	}
+
+	// Point of Control
+	pocIndex := 0
+	for i := range levels {
+		if levels[i].Volume > levels[pocIndex].Volume {
+			pocIndex = i
+		}
+	}
+
+	// Зона стоимости: расширяемся от POC в сторону корзины с большим объемом,
+	// пока не наберем нужную долю общего объема
+	lowIdx, highIdx := pocIndex, pocIndex
+	areaVolume := levels[pocIndex].Volume
+	target := totalVolume * vp.valueArea
+
+	for areaVolume < target && (lowIdx > 0 || highIdx < vp.bins-1) {
+		below := -1.0
+		if lowIdx > 0 {
+			below = levels[lowIdx-1].Volume
+		}
+
+		above := -1.0
+		if highIdx < vp.bins-1 {
+			above = levels[highIdx+1].Volume
+		}
+
+		if above >= below {
+			highIdx++
+			areaVolume += above
+		} else {
+			lowIdx--
+			areaVolume += below
+		}
+	}
+
+	return &VolumeProfileResult{
+		Levels:             levels,
+		POC:                levels[pocIndex].Price(),
+		POCIndex:           pocIndex,
+		ValueAreaHigh:      levels[highIdx].PriceHigh,
+		ValueAreaLow:       levels[lowIdx].PriceLow,
+		ValueAreaLowIndex:  lowIdx,
+		ValueAreaHighIndex: highIdx,
+		TotalVolume:        totalVolume,
+	}
+}
@@ -1,8 +1,36 @@
 package gota
 
+import (
+	"sort"
+	"time"
+)
+
 // Series - интерфейс для работы масивами данных
 type Series interface {
 	Len() int
 	At(index int) Candle
 	Slice(start, end int) Series
 }
+
+// Timeframe определяет таймфрейм серии как медианный интервал между началами соседних свечей.
+// Медиана устойчива к пропускам (выходные, праздники). Для серии из менее чем двух свечей возвращает 0
+func Timeframe(series Series) time.Duration {
+	if series.Len() < 2 {
+		return 0
+	}
+
+	intervals := make([]time.Duration, 0, series.Len()-1)
+	for i := 1; i < series.Len(); i++ {
+		interval := series.At(i).GetStartTime().Sub(series.At(i - 1).GetStartTime())
+		if interval > 0 {
+			intervals = append(intervals, interval)
+		}
+	}
+
+	if len(intervals) == 0 {
+		return 0
+	}
+
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+	return intervals[len(intervals)/2]
+}
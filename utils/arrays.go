@@ -1,5 +1,7 @@
 package utils
 
+import "math"
+
 func AlignLengths(arr1, arr2 []float64) ([]float64, []float64) {
 	aligned := AlignLengthsMulti(arr1, arr2)
 	return aligned[0], aligned[1]
@@ -31,3 +33,38 @@ func AlignLengthsMulti(arrays ...[]float64) [][]float64 {
 
 	return aligned
 }
+
+// PadLengthsMulti выравнивает массивы по последним элементам, дополняя более короткие
+// значениями NaN в начале до длины самого длинного массива
+func PadLengthsMulti(arrays ...[]float64) [][]float64 {
+	maxLength := 0
+	for _, arr := range arrays {
+		if len(arr) > maxLength {
+			maxLength = len(arr)
+		}
+	}
+
+	aligned := make([][]float64, len(arrays))
+	for i, arr := range arrays {
+		aligned[i] = AlignToLength(arr, maxLength)
+	}
+
+	return aligned
+}
+
+// AlignToLength выравнивает данные по последним n элементам: более длинные обрезаются в начале,
+// более короткие дополняются значениями NaN в начале (например, индикатор по свечам серии)
+func AlignToLength(data []float64, n int) []float64 {
+	if len(data) >= n {
+		return data[len(data)-n:]
+	}
+
+	aligned := make([]float64, n)
+	offset := n - len(data)
+	for i := 0; i < offset; i++ {
+		aligned[i] = math.NaN()
+	}
+	copy(aligned[offset:], data)
+
+	return aligned
+}
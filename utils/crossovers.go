@@ -2,6 +2,9 @@ package utils
 
 import (
 	"math"
+	"time"
+
+	"github.com/egor-erm/gota"
 )
 
 // CrossoverType тип пересечения
@@ -19,50 +22,109 @@ const (
 // CrossoverEvent событие пересечения
 type CrossoverEvent struct {
 	Type       CrossoverType
-	Index      int     // Индекс свечи где произошло пересечение
-	Value      float64 // Значение на момент пересечения
-	Line1Value float64 // Значение первой линии
-	Line2Value float64 // Значение второй линии
+	Index      int       // Индекс свечи где произошло пересечение
+	Value      float64   // Значение на момент пересечения
+	Line1Value float64   // Значение первой линии
+	Line2Value float64   // Значение второй линии (уровня)
+	Time       time.Time // Время свечи (заполняется CrossoversWithTimes)
+}
+
+// CrossoverOptions параметры поиска пересечений
+type CrossoverOptions struct {
+	// Epsilon - допуск: разница линий, не превышающая Epsilon по модулю, считается касанием
+	Epsilon float64
+	// KeepSideOnTouch - касание не меняет сторону, на которой находится линия: пересечение
+	// фиксируется только при переходе на противоположную сторону больше чем на Epsilon.
+	// По умолчанию отход от касания в любую сторону считается пересечением в эту сторону
+	KeepSideOnTouch bool
+}
+
+// DefaultCrossoverOptions возвращает параметры по умолчанию
+func DefaultCrossoverOptions() CrossoverOptions {
+	return CrossoverOptions{Epsilon: 1e-10}
 }
 
 // FindCrossovers находит все пересечения между двумя линиями
 func FindCrossovers(line1, line2 []float64) []CrossoverEvent {
-	if len(line1) != len(line2) || len(line1) < 2 {
+	return FindCrossoversWithOptions(line1, line2, DefaultCrossoverOptions())
+}
+
+// FindCrossoversWithOptions находит все пересечения между двумя линиями.
+// Пересечение - переход разницы линий через ноль или отход от касания (см. KeepSideOnTouch).
+// Линии разной длины выравниваются по последним значениям, индексы событий
+// соответствуют более длинной линии. Значения NaN прерывают линию: пересечение
+// через пропуск не фиксируется
+func FindCrossoversWithOptions(line1, line2 []float64, options CrossoverOptions) []CrossoverEvent {
+	aligned := PadLengthsMulti(line1, line2)
+	line1, line2 = aligned[0], aligned[1]
+
+	if len(line1) < 2 {
 		return nil
 	}
 
 	var events []CrossoverEvent
-	for i := 1; i < len(line1); i++ {
-		prevDiff := line1[i-1] - line2[i-1]
-		currDiff := line1[i] - line2[i]
-
-		// Проверяем пересечение
-		if (prevDiff < 0 && currDiff > 0) ||
-			(math.Abs(prevDiff) < 1e-10 && currDiff > 0) {
-			// Бычий кросс: line1 пересекает line2 снизу вверх
-			events = append(events, CrossoverEvent{
-				Type:       BullishCross,
-				Index:      i,
-				Value:      (line1[i] + line2[i]) / 2,
-				Line1Value: line1[i],
-				Line2Value: line2[i],
-			})
-		} else if (prevDiff > 0 && currDiff < 0) ||
-			(math.Abs(prevDiff) < 1e-10 && currDiff < 0) {
-			// Медвежий кросс: line1 пересекает line2 сверху вниз
+	side := 0        // -1: первая линия ниже второй, 1: выше, 0: касание
+	started := false // сторона определена
+
+	for i := range line1 {
+		diff := line1[i] - line2[i]
+		if math.IsNaN(diff) {
+			started = false
+			continue
+		}
+
+		current := 0
+		if diff > options.Epsilon {
+			current = 1
+		} else if diff < -options.Epsilon {
+			current = -1
+		} else if options.KeepSideOnTouch {
+			current = side
+		}
+
+		if started && current != 0 && current != side {
+			crossType := BullishCross // line1 пересекает line2 снизу вверх
+			if current < 0 {
+				crossType = BearishCross // line1 пересекает line2 сверху вниз
+			}
+
 			events = append(events, CrossoverEvent{
-				Type:       BearishCross,
+				Type:       crossType,
 				Index:      i,
 				Value:      (line1[i] + line2[i]) / 2,
 				Line1Value: line1[i],
 				Line2Value: line2[i],
 			})
 		}
+
+		side = current
+		started = started || !options.KeepSideOnTouch || current != 0
 	}
 
 	return events
 }
 
+// FindLevelCrosses находит пересечения линии с постоянным уровнем (например, RSI с 30 и 70).
+// BullishCross - пересечение уровня снизу вверх, BearishCross - сверху вниз
+func FindLevelCrosses(line []float64, level float64, options CrossoverOptions) []CrossoverEvent {
+	levelLine := make([]float64, len(line))
+	for i := range levelLine {
+		levelLine[i] = level
+	}
+
+	return FindCrossoversWithOptions(line, levelLine, options)
+}
+
+// FindCrossAbove находит пересечения уровня снизу вверх
+func FindCrossAbove(line []float64, level float64, options CrossoverOptions) []CrossoverEvent {
+	return filterCrossovers(FindLevelCrosses(line, level, options), BullishCross)
+}
+
+// FindCrossBelow находит пересечения уровня сверху вниз
+func FindCrossBelow(line []float64, level float64, options CrossoverOptions) []CrossoverEvent {
+	return filterCrossovers(FindLevelCrosses(line, level, options), BearishCross)
+}
+
 // FindGoldenCross находит "золотой крест" (быстрая MA пересекает медленную MA снизу вверх)
 func FindGoldenCross(fastMA, slowMA []float64) []CrossoverEvent {
 	return findMACross(fastMA, slowMA, BullishCross)
@@ -75,7 +137,11 @@ func FindDeathCross(fastMA, slowMA []float64) []CrossoverEvent {
 
 // findMACross находит пересечения скользящих средних
 func findMACross(fastMA, slowMA []float64, crossType CrossoverType) []CrossoverEvent {
-	crossovers := FindCrossovers(fastMA, slowMA)
+	return filterCrossovers(FindCrossovers(fastMA, slowMA), crossType)
+}
+
+// filterCrossovers оставляет пересечения заданного типа
+func filterCrossovers(crossovers []CrossoverEvent, crossType CrossoverType) []CrossoverEvent {
 	var result []CrossoverEvent
 
 	for _, crossover := range crossovers {
@@ -86,3 +152,118 @@ func findMACross(fastMA, slowMA []float64, crossType CrossoverType) []CrossoverE
 
 	return result
 }
+
+// BandEventType тип события выхода за границы канала
+type BandEventType int
+
+const (
+	// BandExitAbove - значение вышло из канала выше верхней границы
+	BandExitAbove BandEventType = iota
+	// BandExitBelow - значение вышло из канала ниже нижней границы
+	BandExitBelow
+	// BandEnterFromAbove - значение вернулось в канал сверху
+	BandEnterFromAbove
+	// BandEnterFromBelow - значение вернулось в канал снизу
+	BandEnterFromBelow
+)
+
+// BandEvent событие входа в канал или выхода из него
+type BandEvent struct {
+	Type  BandEventType
+	Index int       // Индекс свечи события
+	Value float64   // Значение ряда
+	Upper float64   // Верхняя граница канала
+	Lower float64   // Нижняя граница канала
+	Time  time.Time // Время свечи (заполняется BandEventsWithTimes)
+}
+
+// FindBandEvents находит моменты выхода значений за границы канала (например, цены
+// за полосы Боллинджера) и возврата в канал. Значение на границе с точностью Epsilon
+// считается находящимся внутри. При переходе через весь канал за одну свечу фиксируются
+// оба события: возврат в канал и выход с противоположной стороны.
+// Ряды разной длины выравниваются по последним значениям, NaN сбрасывает состояние
+func FindBandEvents(values, upper, lower []float64, options CrossoverOptions) []BandEvent {
+	aligned := PadLengthsMulti(values, upper, lower)
+	values, upper, lower = aligned[0], aligned[1], aligned[2]
+
+	var events []BandEvent
+	state := 0       // -1: ниже канала, 0: внутри, 1: выше
+	started := false // состояние определено
+
+	for i := range values {
+		if math.IsNaN(values[i]) || math.IsNaN(upper[i]) || math.IsNaN(lower[i]) {
+			started = false
+			continue
+		}
+
+		current := 0
+		if values[i] > upper[i]+options.Epsilon {
+			current = 1
+		} else if values[i] < lower[i]-options.Epsilon {
+			current = -1
+		}
+
+		if started && current != state {
+			event := BandEvent{Index: i, Value: values[i], Upper: upper[i], Lower: lower[i]}
+
+			// Возврат в канал
+			if state == 1 {
+				event.Type = BandEnterFromAbove
+				events = append(events, event)
+			} else if state == -1 {
+				event.Type = BandEnterFromBelow
+				events = append(events, event)
+			}
+
+			// Выход из канала
+			if current == 1 {
+				event.Type = BandExitAbove
+				events = append(events, event)
+			} else if current == -1 {
+				event.Type = BandExitBelow
+				events = append(events, event)
+			}
+		}
+
+		state, started = current, true
+	}
+
+	return events
+}
+
+// CandleIndex переводит индекс ряда длины length, выровненного по последним свечам серии,
+// в индекс свечи. Возвращает -1, если свечи с таким индексом нет
+func CandleIndex(series gota.Series, length, index int) int {
+	candleIndex := index + series.Len() - length
+	if candleIndex < 0 || candleIndex >= series.Len() {
+		return -1
+	}
+
+	return candleIndex
+}
+
+// CrossoversWithTimes заполняет время свечи у пересечений, найденных в ряду длины length
+func CrossoversWithTimes(events []CrossoverEvent, series gota.Series, length int) []CrossoverEvent {
+	result := make([]CrossoverEvent, len(events))
+	for i, event := range events {
+		if candleIndex := CandleIndex(series, length, event.Index); candleIndex >= 0 {
+			event.Time = series.At(candleIndex).GetStartTime()
+		}
+		result[i] = event
+	}
+
+	return result
+}
+
+// BandEventsWithTimes заполняет время свечи у событий канала, найденных в ряду длины length
+func BandEventsWithTimes(events []BandEvent, series gota.Series, length int) []BandEvent {
+	result := make([]BandEvent, len(events))
+	for i, event := range events {
+		if candleIndex := CandleIndex(series, length, event.Index); candleIndex >= 0 {
+			event.Time = series.At(candleIndex).GetStartTime()
+		}
+		result[i] = event
+	}
+
+	return result
+}
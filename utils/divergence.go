@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// DivergenceType тип дивергенции
+type DivergenceType int
+
+const (
+	// RegularBullish - цена обновляет минимум, осциллятор нет (ослабление нисходящего тренда)
+	RegularBullish DivergenceType = iota
+	// RegularBearish - цена обновляет максимум, осциллятор нет (ослабление восходящего тренда)
+	RegularBearish
+	// HiddenBullish - цена делает более высокий минимум, осциллятор - более низкий (продолжение роста)
+	HiddenBullish
+	// HiddenBearish - цена делает более низкий максимум, осциллятор - более высокий (продолжение падения)
+	HiddenBearish
+)
+
+// DivergenceConfig параметры поиска дивергенций
+type DivergenceConfig struct {
+	LeftBars    int  // Количество свечей слева, которые экстремум должен превосходить
+	RightBars   int  // Количество свечей справа для подтверждения экстремума
+	MinDistance int  // Минимальное расстояние между сравниваемыми экстремумами в свечах
+	MaxDistance int  // Максимальное расстояние между сравниваемыми экстремумами (0 - без ограничения)
+	Regular     bool // Искать обычные дивергенции
+	Hidden      bool // Искать скрытые дивергенции
+}
+
+// DefaultDivergenceConfig возвращает параметры по умолчанию
+func DefaultDivergenceConfig() DivergenceConfig {
+	return DivergenceConfig{
+		LeftBars:    5,
+		RightBars:   5,
+		MinDistance: 5,
+		MaxDistance: 60,
+		Regular:     true,
+		Hidden:      true,
+	}
+}
+
+// Divergence найденная дивергенция между двумя соседними экстремумами цены
+type Divergence struct {
+	Type            DivergenceType
+	StartIndex      int     // Индекс первого экстремума (в координатах цены)
+	EndIndex        int     // Индекс второго экстремума (в координатах цены)
+	StartPrice      float64 // Цена в первом экстремуме
+	EndPrice        float64 // Цена во втором экстремуме
+	StartOscillator float64 // Значение осциллятора в первом экстремуме
+	EndOscillator   float64 // Значение осциллятора во втором экстремуме
+}
+
+// FindDivergences находит дивергенции между ценой и осциллятором. Экстремумы ищутся по highs
+// (максимумы) и lows (минимумы), значения осциллятора берутся в тех же свечах.
+// Осциллятор может быть короче цены - он выравнивается по последним свечам.
+// Дивергенция подтверждается через RightBars свечей после второго экстремума
+func FindDivergences(highs, lows, oscillator []float64, config DivergenceConfig) []Divergence {
+	if len(highs) != len(lows) || len(oscillator) == 0 || len(oscillator) > len(highs) {
+		return nil
+	}
+
+	offset := len(highs) - len(oscillator)
+	oscillatorAt := func(index int) float64 {
+		if index < offset {
+			return math.NaN()
+		}
+		return oscillator[index-offset]
+	}
+
+	var divergences []Divergence
+
+	compare := func(pivots []int, prices []float64, bullish bool) {
+		for k := 1; k < len(pivots); k++ {
+			start, end := pivots[k-1], pivots[k]
+			distance := end - start
+			if distance < config.MinDistance || (config.MaxDistance > 0 && distance > config.MaxDistance) {
+				continue
+			}
+
+			startOsc, endOsc := oscillatorAt(start), oscillatorAt(end)
+			if math.IsNaN(startOsc) || math.IsNaN(endOsc) {
+				continue
+			}
+
+			priceUp := prices[end] > prices[start]
+			priceDown := prices[end] < prices[start]
+			oscUp := endOsc > startOsc
+			oscDown := endOsc < startOsc
+
+			var divType DivergenceType
+			switch {
+			case bullish && config.Regular && priceDown && oscUp:
+				divType = RegularBullish
+			case bullish && config.Hidden && priceUp && oscDown:
+				divType = HiddenBullish
+			case !bullish && config.Regular && priceUp && oscDown:
+				divType = RegularBearish
+			case !bullish && config.Hidden && priceDown && oscUp:
+				divType = HiddenBearish
+			default:
+				continue
+			}
+
+			divergences = append(divergences, Divergence{
+				Type:            divType,
+				StartIndex:      start,
+				EndIndex:        end,
+				StartPrice:      prices[start],
+				EndPrice:        prices[end],
+				StartOscillator: startOsc,
+				EndOscillator:   endOsc,
+			})
+		}
+	}
+
+	compare(FindPivotLows(lows, config.LeftBars, config.RightBars), lows, true)
+	compare(FindPivotHighs(highs, config.LeftBars, config.RightBars), highs, false)
+
+	// Упорядочиваем по моменту второго экстремума
+	sort.SliceStable(divergences, func(i, j int) bool {
+		return divergences[i].EndIndex < divergences[j].EndIndex
+	})
+
+	return divergences
+}
+
+// FindPivotHighs возвращает индексы локальных максимумов: значение строго больше left
+// предыдущих значений и не меньше right последующих
+func FindPivotHighs(values []float64, left, right int) []int {
+	return findPivots(values, left, right, func(a, b float64) bool { return a > b })
+}
+
+// FindPivotLows возвращает индексы локальных минимумов: значение строго меньше left
+// предыдущих значений и не больше right последующих
+func FindPivotLows(values []float64, left, right int) []int {
+	return findPivots(values, left, right, func(a, b float64) bool { return a < b })
+}
+
+// findPivots находит экстремумы, для которых better(value, other) выполняется слева
+// и better(other, value) не выполняется справа
+func findPivots(values []float64, left, right int, better func(a, b float64) bool) []int {
+	var pivots []int
+
+	for i := left; i < len(values)-right; i++ {
+		if math.IsNaN(values[i]) {
+			continue
+		}
+
+		isPivot := true
+		for j := i - left; j < i && isPivot; j++ {
+			isPivot = !math.IsNaN(values[j]) && better(values[i], values[j])
+		}
+		for j := i + 1; j <= i+right && isPivot; j++ {
+			isPivot = !math.IsNaN(values[j]) && !better(values[j], values[i])
+		}
+
+		if isPivot {
+			pivots = append(pivots, i)
+		}
+	}
+
+	return pivots
+}
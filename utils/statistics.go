@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"math"
+)
+
+// Скользящие статистики по произвольному числовому ряду.
+// Результат содержит len(values)-period+1 значений: первое соответствует окну values[0:period]
+
+// RollingMean вычисляет скользящее среднее
+func RollingMean(values []float64, period int) []float64 {
+	return rolling(values, period, func(window []float64) float64 {
+		return mean(window)
+	})
+}
+
+// RollingVariance вычисляет скользящую дисперсию. При sample = true используется
+// выборочная дисперсия (деление на period-1), иначе генеральная (деление на period)
+func RollingVariance(values []float64, period int, sample bool) []float64 {
+	if sample && period < 2 {
+		return nil
+	}
+
+	return rolling(values, period, func(window []float64) float64 {
+		return variance(window, sample)
+	})
+}
+
+// RollingStdDev вычисляет скользящее стандартное отклонение
+func RollingStdDev(values []float64, period int, sample bool) []float64 {
+	if sample && period < 2 {
+		return nil
+	}
+
+	return rolling(values, period, func(window []float64) float64 {
+		return math.Sqrt(variance(window, sample))
+	})
+}
+
+// RollingZScore вычисляет отклонение последнего значения окна от среднего в стандартных отклонениях
+func RollingZScore(values []float64, period int) []float64 {
+	return rolling(values, period, func(window []float64) float64 {
+		stdDev := math.Sqrt(variance(window, false))
+		if stdDev == 0 {
+			return 0
+		}
+
+		return (window[len(window)-1] - mean(window)) / stdDev
+	})
+}
+
+// RollingMin вычисляет скользящий минимум
+func RollingMin(values []float64, period int) []float64 {
+	return rolling(values, period, func(window []float64) float64 {
+		result := window[0]
+		for _, v := range window[1:] {
+			result = math.Min(result, v)
+		}
+
+		return result
+	})
+}
+
+// RollingMax вычисляет скользящий максимум
+func RollingMax(values []float64, period int) []float64 {
+	return rolling(values, period, func(window []float64) float64 {
+		result := window[0]
+		for _, v := range window[1:] {
+			result = math.Max(result, v)
+		}
+
+		return result
+	})
+}
+
+// RollingPercentRank вычисляет процент предыдущих значений окна, которые не больше последнего (от 0 до 100)
+func RollingPercentRank(values []float64, period int) []float64 {
+	if period < 2 {
+		return nil
+	}
+
+	return rolling(values, period, func(window []float64) float64 {
+		current := window[len(window)-1]
+
+		count := 0
+		for _, v := range window[:len(window)-1] {
+			if v <= current {
+				count++
+			}
+		}
+
+		return 100 * float64(count) / float64(len(window)-1)
+	})
+}
+
+// RollingSkewness вычисляет скользящий коэффициент асимметрии
+func RollingSkewness(values []float64, period int) []float64 {
+	return rolling(values, period, func(window []float64) float64 {
+		m2, m3, _ := centralMoments(window)
+		if m2 == 0 {
+			return 0
+		}
+
+		return m3 / math.Pow(m2, 1.5)
+	})
+}
+
+// RollingKurtosis вычисляет скользящий коэффициент эксцесса (для нормального распределения равен 0)
+func RollingKurtosis(values []float64, period int) []float64 {
+	return rolling(values, period, func(window []float64) float64 {
+		m2, _, m4 := centralMoments(window)
+		if m2 == 0 {
+			return 0
+		}
+
+		return m4/(m2*m2) - 3
+	})
+}
+
+// rolling применяет функцию к каждому окну длины period
+func rolling(values []float64, period int, fn func(window []float64) float64) []float64 {
+	if period <= 0 || len(values) < period {
+		return nil
+	}
+
+	result := make([]float64, 0, len(values)-period+1)
+	for i := period - 1; i < len(values); i++ {
+		result = append(result, fn(values[i-period+1:i+1]))
+	}
+
+	return result
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+func variance(values []float64, sample bool) float64 {
+	m := mean(values)
+
+	sumSquares := 0.0
+	for _, v := range values {
+		diff := v - m
+		sumSquares += diff * diff
+	}
+
+	if sample {
+		return sumSquares / float64(len(values)-1)
+	}
+
+	return sumSquares / float64(len(values))
+}
+
+// centralMoments вычисляет второй, третий и четвертый центральные моменты
+func centralMoments(values []float64) (m2, m3, m4 float64) {
+	m := mean(values)
+
+	for _, v := range values {
+		diff := v - m
+		m2 += diff * diff
+		m3 += diff * diff * diff
+		m4 += diff * diff * diff * diff
+	}
+
+	n := float64(len(values))
+	return m2 / n, m3 / n, m4 / n
+}